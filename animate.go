@@ -0,0 +1,28 @@
+package simplex
+
+// AnimatedNoise2 returns 2D noise at (x,y) as it evolves over time t.
+// It is currently just Noise3(x, y, t)
+func (s *Simplex) AnimatedNoise2(x, y, t float64) float64 {
+	return s.Noise3(x, y, t)
+}
+
+// AnimatedFBM2 is the time-varying version of FBM2.  Octave i is
+// sampled at Noise3(x*f, y*f, t*f*timeScale) where f = lacunarity^i,
+// so the finer octaves also change faster.  This avoids the
+// "synchronous pulsing" you get when every octave shares the same
+// time rate.
+func (s *Simplex) AnimatedFBM2(x, y, t float64, octaves int, lacunarity, persistence, timeScale float64) float64 {
+	var sum, total float64
+	f := 1.0
+	a := 1.0
+	for i := 0; i < octaves; i++ {
+		sum += a * s.Noise3(x*f, y*f, t*f*timeScale)
+		total += a
+		f *= lacunarity
+		a *= persistence
+	}
+	if total == 0 {
+		return 0
+	}
+	return sum / total
+}
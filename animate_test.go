@@ -0,0 +1,65 @@
+package simplex
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestAnimatedNoise2(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	a := n.AnimatedNoise2(0.3, 1.25, 0)
+	a0 := n.Noise3(0.3, 1.25, 0)
+	if a != a0 {
+		t.Errorf("Got %.4f, expected %.4f", a, a0)
+	}
+
+	const eps = 1e-6
+	for i := 0; i < 10000; i++ {
+		x := r.Float64() * 10
+		y := r.Float64() * 10
+		tm := r.Float64() * 10
+		a := n.AnimatedNoise2(x, y, tm)
+		for _, b := range []float64{
+			n.AnimatedNoise2(x+eps, y, tm),
+			n.AnimatedNoise2(x, y+eps, tm),
+			n.AnimatedNoise2(x, y, tm+eps),
+		} {
+			if math.Abs(a-b) > 1e-4 {
+				t.Fatalf("got jump from %.6f to %.6f near (%g, %g, %g)", a, b, x, y, tm)
+			}
+		}
+	}
+}
+
+func TestAnimatedFBM2(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	// the second octave runs at lacunarity times the time rate
+	x, y, tm := 0.3, 1.25, 0.7
+	a := n.AnimatedFBM2(x, y, tm, 2, 3, 0.5, 0.25)
+	a0 := (n.Noise3(x, y, tm*0.25) + 0.5*n.Noise3(3*x, 3*y, 3*tm*0.25)) / 1.5
+	if math.Abs(a-a0) > 1e-12 {
+		t.Errorf("Got %.4f, expected %.4f", a, a0)
+	}
+
+	const eps = 1e-6
+	for i := 0; i < 10000; i++ {
+		x := r.Float64() * 10
+		y := r.Float64() * 10
+		tm := r.Float64() * 10
+		a := n.AnimatedFBM2(x, y, tm, 5, 2, 0.5, 1)
+		for _, b := range []float64{
+			n.AnimatedFBM2(x+eps, y, tm, 5, 2, 0.5, 1),
+			n.AnimatedFBM2(x, y+eps, tm, 5, 2, 0.5, 1),
+			n.AnimatedFBM2(x, y, tm+eps, 5, 2, 0.5, 1),
+		} {
+			if math.Abs(a-b) > 1e-3 {
+				t.Fatalf("got jump from %.6f to %.6f near (%g, %g, %g)", a, b, x, y, tm)
+			}
+		}
+	}
+}
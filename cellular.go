@@ -0,0 +1,143 @@
+package simplex
+
+import (
+	"math"
+)
+
+// Worley (cellular) noise: every unit lattice cell holds one feature
+// point, placed by hashing the cell coordinates through the
+// permutation table, and the noise is the distance to the nearest
+// (F1) and second nearest (F2) feature points.
+
+// featurePoint2 returns the feature point within lattice cell (i, j)
+func (s *Simplex) featurePoint2(i, j int) (float64, float64) {
+	h := s.getPerm(i + s.getPerm(j))
+	jx := (float64(h) + 0.5) / 256
+	jy := (float64(s.getPerm(h+j)) + 0.5) / 256
+	return float64(i) + jx, float64(j) + jy
+}
+
+// cellular2 searches the 3x3 block of cells around (x, y) for the two
+// nearest feature points under the given distance metric.  It also
+// returns the location of the nearest one.
+func (s *Simplex) cellular2(x, y float64, dist func(dx, dy float64) float64) (f1, f2, cx, cy float64) {
+	i := fastfloor(x)
+	j := fastfloor(y)
+	f1 = math.Inf(1)
+	f2 = math.Inf(1)
+	for dj := -1; dj <= 1; dj++ {
+		for di := -1; di <= 1; di++ {
+			px, py := s.featurePoint2(i+di, j+dj)
+			d := dist(px-x, py-y)
+			if d < f1 {
+				f2 = f1
+				f1 = d
+				cx, cy = px, py
+			} else if d < f2 {
+				f2 = d
+			}
+		}
+	}
+	return f1, f2, cx, cy
+}
+
+func euclidean2(dx, dy float64) float64 {
+	return math.Sqrt(dx*dx + dy*dy)
+}
+
+// CellularNoise2 returns the Euclidean distances from (x, y) to the
+// nearest and second nearest feature points (so 0 <= f1 <= f2)
+func (s *Simplex) CellularNoise2(x, y float64) (f1, f2 float64) {
+	f1, f2, _, _ = s.cellular2(x, y, euclidean2)
+	return f1, f2
+}
+
+// WorleyFBM2 sums octaves of the F1 distance from CellularNoise2 the
+// way FBM2 sums octaves of Noise2.  The point in a cell is never more
+// than sqrt(2) from anywhere in it, so F1 is divided by sqrt(2) and the
+// result is in [0,1].
+func (s *Simplex) WorleyFBM2(x, y float64, octaves int, lacunarity, persistence float64) float64 {
+	var sum, total float64
+	f := 1.0
+	a := 1.0
+	for i := 0; i < octaves; i++ {
+		f1, _ := s.CellularNoise2(x*f, y*f)
+		sum += a * f1 / math.Sqrt2
+		total += a
+		f *= lacunarity
+		a *= persistence
+	}
+	if total == 0 {
+		return 0
+	}
+	return sum / total
+}
+
+// featurePoint3 returns the feature point within lattice cell (i, j, k)
+func (s *Simplex) featurePoint3(i, j, k int) (float64, float64, float64) {
+	h := s.getPerm(i + s.getPerm(j+s.getPerm(k)))
+	jx := (float64(h) + 0.5) / 256
+	jy := (float64(s.getPerm(h+j)) + 0.5) / 256
+	jz := (float64(s.getPerm(h+k+128)) + 0.5) / 256
+	return float64(i) + jx, float64(j) + jy, float64(k) + jz
+}
+
+// CellularNoise3 is the 3D version of CellularNoise2, searching the
+// 3x3x3 block of cells around (x, y, z)
+func (s *Simplex) CellularNoise3(x, y, z float64) (f1, f2 float64) {
+	i := fastfloor(x)
+	j := fastfloor(y)
+	k := fastfloor(z)
+	f1 = math.Inf(1)
+	f2 = math.Inf(1)
+	for dk := -1; dk <= 1; dk++ {
+		for dj := -1; dj <= 1; dj++ {
+			for di := -1; di <= 1; di++ {
+				px, py, pz := s.featurePoint3(i+di, j+dj, k+dk)
+				dx := px - x
+				dy := py - y
+				dz := pz - z
+				d := math.Sqrt(dx*dx + dy*dy + dz*dz)
+				if d < f1 {
+					f2 = f1
+					f1 = d
+				} else if d < f2 {
+					f2 = d
+				}
+			}
+		}
+	}
+	return f1, f2
+}
+
+func manhattan2(dx, dy float64) float64 {
+	return math.Abs(dx) + math.Abs(dy)
+}
+
+// ManhattanCellular2 is CellularNoise2 using the L1 (|dx|+|dy|)
+// metric, which gives diamond-faceted cells with axis-aligned and
+// diagonal boundaries
+func (s *Simplex) ManhattanCellular2(x, y float64) (f1, f2 float64) {
+	f1, f2, _, _ = s.cellular2(x, y, manhattan2)
+	return f1, f2
+}
+
+func chebyshev2(dx, dy float64) float64 {
+	return math.Max(math.Abs(dx), math.Abs(dy))
+}
+
+// ChebyshevCellular2 is CellularNoise2 using the L∞ (max(|dx|,|dy|))
+// metric, which gives square cells.  Together with CellularNoise2 and
+// ManhattanCellular2 this covers the three classic Lp metrics.
+func (s *Simplex) ChebyshevCellular2(x, y float64) (f1, f2 float64) {
+	f1, f2, _, _ = s.cellular2(x, y, chebyshev2)
+	return f1, f2
+}
+
+// VoronoiCenter2 returns the feature point nearest to (x, y), i.e.,
+// the center of the Voronoi cell containing it, using the same points
+// as CellularNoise2
+func (s *Simplex) VoronoiCenter2(x, y float64) (cx, cy float64) {
+	_, _, cx, cy = s.cellular2(x, y, euclidean2)
+	return cx, cy
+}
@@ -0,0 +1,275 @@
+package simplex
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestCellularNoise2(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	const eps = 0.001
+	var under [5]int
+	const samples = 100000
+	for i := 0; i < samples; i++ {
+		x := r.Float64()*200 - 100
+		y := r.Float64()*200 - 100
+		f1, f2 := n.CellularNoise2(x, y)
+		if f1 < 0 || f1 > f2 {
+			t.Fatalf("got f1=%.4f f2=%.4f at (%g, %g), expected 0 <= f1 <= f2", f1, f2, x, y)
+		}
+		// F1 is 1-Lipschitz, so it can't jump by more than the step
+		g1, _ := n.CellularNoise2(x+eps, y)
+		h1, _ := n.CellularNoise2(x, y+eps)
+		if math.Abs(g1-f1) > eps*1.0001 || math.Abs(h1-f1) > eps*1.0001 {
+			t.Fatalf("got jump from %.6f to %.6f / %.6f near (%g, %g)", f1, g1, h1, x, y)
+		}
+		for k := range under {
+			if f1 < 0.1*float64(k+1) {
+				under[k]++
+			}
+		}
+	}
+
+	// compare with the same process (one uniformly placed point
+	// per unit cell) using independent random numbers
+	var ref [5]int
+	for i := 0; i < samples; i++ {
+		x := r.Float64()
+		y := r.Float64()
+		f1 := math.Inf(1)
+		for dj := -1; dj <= 1; dj++ {
+			for di := -1; di <= 1; di++ {
+				px := float64(di) + r.Float64()
+				py := float64(dj) + r.Float64()
+				f1 = math.Min(f1, math.Hypot(px-x, py-y))
+			}
+		}
+		for k := range ref {
+			if f1 < 0.1*float64(k+1) {
+				ref[k]++
+			}
+		}
+	}
+	for k := range under {
+		got := float64(under[k]) / samples
+		expect := float64(ref[k]) / samples
+		if math.Abs(got-expect) > 0.02 {
+			t.Errorf("got P(f1 < %.1f) = %.4f, expected %.4f", 0.1*float64(k+1), got, expect)
+		}
+	}
+}
+
+func TestCellularNoise3(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	const eps = 0.001
+	for i := 0; i < 100000; i++ {
+		x := r.Float64()*200 - 100
+		y := r.Float64()*200 - 100
+		z := r.Float64()*200 - 100
+		f1, f2 := n.CellularNoise3(x, y, z)
+		if f1 < 0 || f1 > f2 {
+			t.Fatalf("got f1=%.4f f2=%.4f at (%g, %g, %g), expected 0 <= f1 <= f2", f1, f2, x, y, z)
+		}
+		for _, g := range []float64{
+			first(n.CellularNoise3(x+eps, y, z)),
+			first(n.CellularNoise3(x, y+eps, z)),
+			first(n.CellularNoise3(x, y, z+eps)),
+		} {
+			if math.Abs(g-f1) > eps*1.0001 {
+				t.Fatalf("got jump from %.6f to %.6f near (%g, %g, %g)", f1, g, x, y, z)
+			}
+		}
+	}
+}
+
+func first(f1, f2 float64) float64 {
+	return f1
+}
+
+// compares the per-sample cost of 2D and 3D cellular noise
+func BenchmarkCellularNoise3(b *testing.B) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	b.Run("2D", func(b *testing.B) {
+		x := 0.001
+		y := 0.0001
+		for i := 0; i < b.N; i++ {
+			n.CellularNoise2(x, y)
+			x += 0.00000011
+			y += 0.00000012
+		}
+	})
+	b.Run("3D", func(b *testing.B) {
+		x := 0.001
+		y := 0.0001
+		z := 0.00001
+		for i := 0; i < b.N; i++ {
+			n.CellularNoise3(x, y, z)
+			x += 0.00000011
+			y += 0.00000012
+			z += 0.00000013
+		}
+	})
+}
+
+// facetFraction returns the fraction of sampled cell boundaries that
+// run horizontally, vertically or at 45 degrees.  The boundary
+// direction is found from the gradient of f2-f1, which is zero on the
+// boundary.
+func facetFraction(cell func(x, y float64) (float64, float64)) float64 {
+	r := rand.New(rand.NewSource(9))
+	const eps = 1e-6
+	edge := func(x, y float64) float64 {
+		f1, f2 := cell(x, y)
+		return f2 - f1
+	}
+	var near, aligned int
+	for near < 2000 {
+		x := r.Float64() * 100
+		y := r.Float64() * 100
+		if edge(x, y) > 0.05 {
+			continue
+		}
+		gx := edge(x+eps, y) - edge(x-eps, y)
+		gy := edge(x, y+eps) - edge(x, y-eps)
+		if gx == 0 && gy == 0 {
+			continue
+		}
+		near++
+		a := math.Mod(math.Atan2(gy, gx)+2*math.Pi, math.Pi/4)
+		if math.Min(a, math.Pi/4-a) < 0.02 {
+			aligned++
+		}
+	}
+	return float64(aligned) / float64(near)
+}
+
+func TestManhattanCellular2(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	const eps = 0.001
+	for i := 0; i < 100000; i++ {
+		x := r.Float64()*200 - 100
+		y := r.Float64()*200 - 100
+		f1, f2 := n.ManhattanCellular2(x, y)
+		if f1 < 0 || f1 > f2 {
+			t.Fatalf("got f1=%.4f f2=%.4f at (%g, %g), expected 0 <= f1 <= f2", f1, f2, x, y)
+		}
+		g1, _ := n.ManhattanCellular2(x+eps, y)
+		h1, _ := n.ManhattanCellular2(x, y+eps)
+		if math.Abs(g1-f1) > eps*1.0001 || math.Abs(h1-f1) > eps*1.0001 {
+			t.Fatalf("got jump from %.6f to %.6f / %.6f near (%g, %g)", f1, g1, h1, x, y)
+		}
+	}
+
+	fl1 := facetFraction(n.ManhattanCellular2)
+	fl2 := facetFraction(n.CellularNoise2)
+	if fl1 < 0.9 || fl1 < 2*fl2 {
+		t.Errorf("got %.3f of L1 cell boundaries along the axes or diagonals (vs %.3f for Euclidean), expected nearly all", fl1, fl2)
+	}
+}
+
+func TestChebyshevCellular2(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	for i := 0; i < 100000; i++ {
+		x := r.Float64()*200 - 100
+		y := r.Float64()*200 - 100
+		f1, f2 := n.ChebyshevCellular2(x, y)
+		if f1 < 0 || f1 > f2 {
+			t.Fatalf("got f1=%.4f f2=%.4f at (%g, %g), expected 0 <= f1 <= f2", f1, f2, x, y)
+		}
+	}
+
+	fl := facetFraction(n.ChebyshevCellular2)
+	if fl < 0.9 {
+		t.Errorf("got %.3f of L∞ cell boundaries along the axes or diagonals, expected nearly all", fl)
+	}
+}
+
+func TestVoronoiCenter2(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	for i := 0; i < 100000; i++ {
+		x := r.Float64()*200 - 100
+		y := r.Float64()*200 - 100
+		cx, cy := n.VoronoiCenter2(x, y)
+		f1, _ := n.CellularNoise2(x, y)
+		if d := math.Hypot(cx-x, cy-y); math.Abs(d-f1) > 1e-12 {
+			t.Fatalf("got center %.4f away from (%g, %g), expected f1=%.4f", d, x, y, f1)
+		}
+		if c1, _ := n.CellularNoise2(cx, cy); c1 > 1e-12 {
+			t.Fatalf("got f1=%g at the center (%g, %g), expected 0", c1, cx, cy)
+		}
+	}
+}
+
+func TestWorleyFBM2(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	for i := 0; i < 100000; i++ {
+		x := r.Float64()*200 - 100
+		y := r.Float64()*200 - 100
+		if a := n.WorleyFBM2(x, y, 5, 2, 0.5); a < 0 || a > 1 {
+			t.Fatalf("Got %.4f at (%g,%g), expected value in [0,1]", a, x, y)
+		}
+		f1, _ := n.CellularNoise2(x, y)
+		if a := n.WorleyFBM2(x, y, 1, 2, 0.5); math.Abs(a-f1/math.Sqrt2) > 1e-12 {
+			t.Fatalf("Got %.4f at (%g,%g), expected %.4f", a, x, y, f1/math.Sqrt2)
+		}
+	}
+
+	// the higher octaves add fine creases, which show up as kinks in
+	// the second difference over a small step
+	roughness := func(octaves int) float64 {
+		const h = 0.05
+		var sum float64
+		for i := 0; i < 10000; i++ {
+			x := r.Float64()*200 - 100
+			y := r.Float64()*200 - 100
+			sum += math.Abs(n.WorleyFBM2(x+h, y, octaves, 2, 0.5) - 2*n.WorleyFBM2(x, y, octaves, 2, 0.5) + n.WorleyFBM2(x-h, y, octaves, 2, 0.5))
+		}
+		return sum
+	}
+	if one, four := roughness(1), roughness(4); four < 2*one {
+		t.Errorf("Got roughness %.4f with 4 octaves, expected well above %.4f with 1", four, one)
+	}
+}
+
+// expect 2 to 3 times BenchmarkSimplex for each metric (about 60-75
+// ns/op where BenchmarkSimplex takes 25); the nine feature points are
+// cheap to hash, so this is less than the 5-10x often quoted for Worley
+// noise
+func BenchmarkCellular2(b *testing.B) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	for _, m := range []struct {
+		name string
+		f    func(x, y float64) (float64, float64)
+	}{
+		{"Euclidean", n.CellularNoise2},
+		{"Manhattan", n.ManhattanCellular2},
+		{"Chebyshev", n.ChebyshevCellular2},
+	} {
+		b.Run(m.name, func(b *testing.B) {
+			x := 0.001
+			y := 0.0001
+			for i := 0; i < b.N; i++ {
+				m.f(x, y)
+				x += 0.00000011
+				y += 0.00000012
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package simplex
+
+// BlendNoise2 mixes two noise fields using a third as the mask: where
+// the mask is -1 the result is a's noise, where it is +1 it is b's, and
+// in between it is a linear blend.  Since all three are smooth, so are
+// the transitions, which makes it handy for biome-like regions.
+func BlendNoise2(a, b *Simplex, mask *Simplex, x, y float64) float64 {
+	return blendByMask(a.Noise2(x, y), b.Noise2(x, y), mask.Noise2(x, y))
+}
+
+// blendByMask blends from va to vb as m goes from -1 to 1
+func blendByMask(va, vb, m float64) float64 {
+	return lerp(va, vb, (m+1)/2)
+}
+
+// SelectNoise2 picks a's noise where the control noise is below
+// threshold and b's where it is above.  The switch is smoothed over a
+// band of width 2*falloff centered on the threshold; with a falloff of
+// 0 it is a hard edge.
+func SelectNoise2(a, b *Simplex, control *Simplex, threshold, falloff float64, x, y float64) float64 {
+	return selectByControl(a.Noise2(x, y), b.Noise2(x, y), control.Noise2(x, y), threshold, falloff)
+}
+
+func selectByControl(va, vb, c, threshold, falloff float64) float64 {
+	if falloff <= 0 {
+		if c < threshold {
+			return va
+		}
+		return vb
+	}
+	return lerp(va, vb, smoothstep((c-threshold+falloff)/(2*falloff)))
+}
@@ -0,0 +1,77 @@
+package simplex
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestBlendNoise2(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+	a := New(r)
+	b := New(r)
+	mask := New(r)
+
+	if v := blendByMask(0.25, -0.5, -1); v != 0.25 {
+		t.Errorf("Got %.4f with mask -1, expected %.4f", v, 0.25)
+	}
+	if v := blendByMask(0.25, -0.5, 1); v != -0.5 {
+		t.Errorf("Got %.4f with mask 1, expected %.4f", v, -0.5)
+	}
+
+	const eps = 1e-6
+	for i := 0; i < 10000; i++ {
+		x := r.Float64() * 10
+		y := r.Float64() * 10
+		v := BlendNoise2(a, b, mask, x, y)
+		va := a.Noise2(x, y)
+		vb := b.Noise2(x, y)
+		if v < math.Min(va, vb)-1e-12 || v > math.Max(va, vb)+1e-12 {
+			t.Fatalf("got %.4f, expected a value between %.4f and %.4f", v, va, vb)
+		}
+		w := BlendNoise2(a, b, mask, x+eps, y+eps)
+		if math.Abs(w-v) > 1e-4 {
+			t.Fatalf("got jump from %.6f to %.6f near (%g, %g)", v, w, x, y)
+		}
+	}
+}
+
+func TestSelectNoise2(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+	a := New(r)
+	b := New(r)
+	control := New(r)
+
+	// hard selection
+	for i := 0; i < 10000; i++ {
+		x := r.Float64() * 10
+		y := r.Float64() * 10
+		v := SelectNoise2(a, b, control, 0.2, 0, x, y)
+		want := a.Noise2(x, y)
+		if control.Noise2(x, y) >= 0.2 {
+			want = b.Noise2(x, y)
+		}
+		if v != want {
+			t.Fatalf("Got %.4f, expected %.4f", v, want)
+		}
+	}
+
+	// smooth, monotone transition centered on the threshold
+	prev := selectByControl(-0.5, 0.5, -1, 0.2, 0.1)
+	if prev != -0.5 {
+		t.Errorf("Got %.4f well below the threshold, expected %.4f", prev, -0.5)
+	}
+	for c := -1.0; c <= 1; c += 0.001 {
+		v := selectByControl(-0.5, 0.5, c, 0.2, 0.1)
+		if v < prev {
+			t.Fatalf("got %.4f after %.4f at control %.3f, expected a monotone transition", v, prev, c)
+		}
+		prev = v
+	}
+	if prev != 0.5 {
+		t.Errorf("Got %.4f well above the threshold, expected %.4f", prev, 0.5)
+	}
+	if v := selectByControl(-0.5, 0.5, 0.2, 0.2, 0.1); math.Abs(v) > 1e-12 {
+		t.Errorf("Got %.4f at the threshold, expected the midpoint 0", v)
+	}
+}
@@ -0,0 +1,56 @@
+package simplex
+
+import (
+	"math"
+)
+
+// PolarNoise2 is Noise2 at the point with polar coordinates (r, theta).
+// It is periodic in theta with period 2π, since theta and theta+2π are
+// the same point, but sweeping theta at a fixed r does not make a good
+// texture axis: the samples all lie on one circle of radius r, so the
+// features get stretched or squeezed as r changes and bunch up toward
+// the origin.  For a texture that wraps seamlessly along an axis, use
+// SeamlessNoise2 (or SeamlessOctaveNoise2) instead.
+func (s *Simplex) PolarNoise2(r, theta float64) float64 {
+	return s.Noise2(r*math.Cos(theta), r*math.Sin(theta))
+}
+
+// SphericalCoordNoise is Noise3 at the point with spherical coordinates
+// (rho, theta, phi), where theta is the azimuth and phi the angle from
+// the +z axis.  At the poles (phi = 0 or π) every theta maps to the
+// same point, so the result is continuous there because Noise3 is.
+func (s *Simplex) SphericalCoordNoise(rho, theta, phi float64) float64 {
+	sp := math.Sin(phi)
+	return s.Noise3(rho*sp*math.Cos(theta), rho*sp*math.Sin(theta), rho*math.Cos(phi))
+}
+
+// RotatedNoise2 is Noise2 with the input rotated by angle (in radians)
+// about the origin.  Giving successive octaves different rotations
+// keeps their lattice artifacts from lining up.
+func (s *Simplex) RotatedNoise2(x, y, angle float64) float64 {
+	c := math.Cos(angle)
+	sn := math.Sin(angle)
+	return s.Noise2(x*c-y*sn, x*sn+y*c)
+}
+
+// AnisotropicNoise2 is Noise2 with each axis scaled by its own
+// frequency, which stretches the features along the lower frequency
+// axis.
+func (s *Simplex) AnisotropicNoise2(x, y, freqX, freqY float64) float64 {
+	return s.Noise2(x*freqX, y*freqY)
+}
+
+// StretchedNoise3 is the 3D version of AnisotropicNoise2.  In layered
+// geology the vertical frequency is typically much higher than the
+// horizontal ones.
+func (s *Simplex) StretchedNoise3(x, y, z, freqX, freqY, freqZ float64) float64 {
+	return s.Noise3(x*freqX, y*freqY, z*freqZ)
+}
+
+// ShiftedNoise2 is Noise2 sampled at an offset.  A single Simplex can
+// provide several effectively independent channels by sampling each
+// one at its own offset, as long as the offsets are well separated (at
+// least 100 units apart).
+func (s *Simplex) ShiftedNoise2(x, y, offsetX, offsetY float64) float64 {
+	return s.Noise2(x+offsetX, y+offsetY)
+}
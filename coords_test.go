@@ -0,0 +1,220 @@
+package simplex
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestPolarNoise2(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	for i := 0; i < 1000; i++ {
+		rho := r.Float64() * 10
+		theta := r.Float64() * 2 * math.Pi
+		a := n.PolarNoise2(rho, theta)
+		a0 := n.Noise2(rho*math.Cos(theta), rho*math.Sin(theta))
+		if a != a0 {
+			t.Fatalf("Got %.4f, expected %.4f", a, a0)
+		}
+	}
+}
+
+func TestSphericalCoordNoise(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	for i := 0; i < 1000; i++ {
+		rho := r.Float64() * 10
+		theta := r.Float64() * 2 * math.Pi
+		phi := r.Float64() * math.Pi
+		a := n.SphericalCoordNoise(rho, theta, phi)
+		a0 := n.Noise3(
+			rho*math.Sin(phi)*math.Cos(theta),
+			rho*math.Sin(phi)*math.Sin(theta),
+			rho*math.Cos(phi))
+		if a != a0 {
+			t.Fatalf("Got %.4f, expected %.4f", a, a0)
+		}
+	}
+
+	// at the poles, the azimuth doesn't matter
+	for _, phi := range []float64{0, math.Pi} {
+		a0 := n.SphericalCoordNoise(2.5, 0, phi)
+		for i := 0; i < 100; i++ {
+			theta := r.Float64() * 2 * math.Pi
+			a := n.SphericalCoordNoise(2.5, theta, phi)
+			if math.Abs(a-a0) > 1e-9 {
+				t.Fatalf("Got %.4f at theta=%g phi=%g, expected %.4f", a, theta, phi, a0)
+			}
+		}
+	}
+}
+
+// latticeCorrelation2 measures the correlation between the
+// magnitudes of two fields near the lattice vertices of Noise2, which
+// is where the noise vanishes
+func latticeCorrelation2(f, g func(x, y float64) float64) float64 {
+	r := rand.New(rand.NewSource(9))
+	var a, b []float64
+	for i := 0; i < 20000; i++ {
+		li := float64(r.Intn(200) - 100)
+		lj := float64(r.Intn(200) - 100)
+		x := li - (li+lj)*G2 + (r.Float64()-0.5)*0.3
+		y := lj - (li+lj)*G2 + (r.Float64()-0.5)*0.3
+		a = append(a, math.Abs(f(x, y)))
+		b = append(b, math.Abs(g(x, y)))
+	}
+	return correlation(a, b)
+}
+
+// correlation returns the Pearson correlation of two samples
+func correlation(a, b []float64) float64 {
+	var ma, mb float64
+	for i := range a {
+		ma += a[i]
+		mb += b[i]
+	}
+	ma /= float64(len(a))
+	mb /= float64(len(b))
+	var sab, saa, sbb float64
+	for i := range a {
+		sab += (a[i] - ma) * (b[i] - mb)
+		saa += (a[i] - ma) * (a[i] - ma)
+		sbb += (b[i] - mb) * (b[i] - mb)
+	}
+	return sab / math.Sqrt(saa*sbb)
+}
+
+func TestRotatedNoise2(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	for i := 0; i < 1000; i++ {
+		x := r.Float64() * 10
+		y := r.Float64() * 10
+		a0 := n.Noise2(x, y)
+		if a := n.RotatedNoise2(x, y, 0); a != a0 {
+			t.Fatalf("Got %.4f, expected %.4f", a, a0)
+		}
+		if a := n.RotatedNoise2(x, y, 2*math.Pi); math.Abs(a-a0) > 1e-9 {
+			t.Fatalf("Got %.4f after a full turn, expected %.4f", a, a0)
+		}
+	}
+
+	// unrotated octaves share lattice vertices, so they tend to
+	// vanish together
+	plain := latticeCorrelation2(n.Noise2, func(x, y float64) float64 {
+		return n.Noise2(2*x, 2*y)
+	})
+	rotated := latticeCorrelation2(n.Noise2, func(x, y float64) float64 {
+		return n.RotatedNoise2(2*x, 2*y, math.Pi/4)
+	})
+	if rotated >= plain {
+		t.Errorf("got correlation %.4f with rotation, expected less than %.4f without", rotated, plain)
+	}
+}
+
+// correlationLength returns the smallest offset along (dx, dy) at
+// which the autocorrelation of f falls below one half
+func correlationLength(f func(x, y float64) float64, dx, dy float64) float64 {
+	const step = 0.005
+	r := rand.New(rand.NewSource(9))
+	var px, py []float64
+	for i := 0; i < 5000; i++ {
+		px = append(px, r.Float64()*100)
+		py = append(py, r.Float64()*100)
+	}
+	a := make([]float64, len(px))
+	b := make([]float64, len(px))
+	for i := range px {
+		a[i] = f(px[i], py[i])
+	}
+	for lag := step; lag < 10; lag += step {
+		for i := range px {
+			b[i] = f(px[i]+lag*dx, py[i]+lag*dy)
+		}
+		if correlation(a, b) < 0.5 {
+			return lag
+		}
+	}
+	return math.Inf(1)
+}
+
+func TestAnisotropicNoise2(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	for i := 0; i < 1000; i++ {
+		x := r.Float64() * 10
+		y := r.Float64() * 10
+		a := n.AnisotropicNoise2(x, y, 1, 1)
+		a0 := n.Noise2(x, y)
+		if a != a0 {
+			t.Fatalf("Got %.4f, expected %.4f", a, a0)
+		}
+	}
+
+	f := func(x, y float64) float64 {
+		return n.AnisotropicNoise2(x, y, 4, 1)
+	}
+	lx := correlationLength(f, 1, 0)
+	ly := correlationLength(f, 0, 1)
+	if ratio := ly / lx; ratio < 3 || ratio > 5 {
+		t.Errorf("got correlation lengths %.3f (x) and %.3f (y), expected a ratio of about 4", lx, ly)
+	}
+}
+
+func TestStretchedNoise3(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	for i := 0; i < 1000; i++ {
+		x := r.Float64() * 10
+		y := r.Float64() * 10
+		z := r.Float64() * 10
+		a := n.StretchedNoise3(x, y, z, 1, 1, 1)
+		a0 := n.Noise3(x, y, z)
+		if a != a0 {
+			t.Fatalf("Got %.4f, expected %.4f", a, a0)
+		}
+	}
+
+	// look at an x-z plane and an x-y plane
+	xz := func(x, z float64) float64 {
+		return n.StretchedNoise3(x, 0.37, z, 1, 2, 4)
+	}
+	xy := func(x, y float64) float64 {
+		return n.StretchedNoise3(x, y, 0.37, 1, 2, 4)
+	}
+	lx := correlationLength(xz, 1, 0)
+	ly := correlationLength(xy, 0, 1)
+	lz := correlationLength(xz, 0, 1)
+	if ratio := lx / ly; ratio < 1.5 || ratio > 2.5 {
+		t.Errorf("got correlation lengths %.3f (x) and %.3f (y), expected a ratio of about 2", lx, ly)
+	}
+	if ratio := lx / lz; ratio < 3 || ratio > 5 {
+		t.Errorf("got correlation lengths %.3f (x) and %.3f (z), expected a ratio of about 4", lx, lz)
+	}
+}
+
+func TestShiftedNoise2(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	var a, b []float64
+	for i := 0; i < 10000; i++ {
+		x := r.Float64() * 100
+		y := r.Float64() * 100
+		a0 := n.ShiftedNoise2(x, y, 0, 0)
+		if want := n.Noise2(x, y); a0 != want {
+			t.Fatalf("Got %.4f, expected %.4f", a0, want)
+		}
+		a = append(a, a0)
+		b = append(b, n.ShiftedNoise2(x, y, 100, 100))
+	}
+	if c := correlation(a, b); math.Abs(c) > 0.05 {
+		t.Errorf("got correlation %.4f between shifted channels, expected about 0", c)
+	}
+}
@@ -0,0 +1,25 @@
+package simplex
+
+// MultiOctaveCurl2 returns a swirling, divergence-free flow at (x, y):
+// the curl (dP/dy, -dP/dx) of the potential P formed by summing
+// octaves of Noise2 as FBM2 does.  The derivatives are central
+// differences over eps.  Since the curl of each octave is divergence
+// free, so is the sum; the faster octaves add small eddies.
+func (s *Simplex) MultiOctaveCurl2(x, y float64, octaves int, lacunarity, persistence, eps float64) (vx, vy float64) {
+	var total float64
+	f := 1.0
+	a := 1.0
+	for i := 0; i < octaves; i++ {
+		dx := s.Noise2((x+eps)*f, y*f) - s.Noise2((x-eps)*f, y*f)
+		dy := s.Noise2(x*f, (y+eps)*f) - s.Noise2(x*f, (y-eps)*f)
+		vx += a * dy / (2 * eps)
+		vy -= a * dx / (2 * eps)
+		total += a
+		f *= lacunarity
+		a *= persistence
+	}
+	if total == 0 {
+		return 0, 0
+	}
+	return vx / total, vy / total
+}
@@ -0,0 +1,58 @@
+package simplex
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestMultiOctaveCurl2(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	const eps = 1e-4
+	const h = 1e-4
+	var worst, scale float64
+	for i := 0; i < 10000; i++ {
+		x := r.Float64() * 100
+		y := r.Float64() * 100
+		vx1, _ := n.MultiOctaveCurl2(x+h, y, 5, 2, 0.5, eps)
+		vx0, _ := n.MultiOctaveCurl2(x-h, y, 5, 2, 0.5, eps)
+		_, vy1 := n.MultiOctaveCurl2(x, y+h, 5, 2, 0.5, eps)
+		_, vy0 := n.MultiOctaveCurl2(x, y-h, 5, 2, 0.5, eps)
+		dvx := (vx1 - vx0) / (2 * h)
+		dvy := (vy1 - vy0) / (2 * h)
+		worst = math.Max(worst, math.Abs(dvx+dvy))
+		scale = math.Max(scale, math.Max(math.Abs(dvx), math.Abs(dvy)))
+	}
+	if worst > 1e-3*scale {
+		t.Errorf("got divergence %.6f, expected about 0 (the derivatives themselves reach %.4f)", worst, scale)
+	}
+
+	// one octave is the curl of plain noise
+	vx, vy := n.MultiOctaveCurl2(0.3, 1.25, 1, 2, 0.5, eps)
+	ex := (n.Noise2(0.3, 1.25+eps) - n.Noise2(0.3, 1.25-eps)) / (2 * eps)
+	ey := -(n.Noise2(0.3+eps, 1.25) - n.Noise2(0.3-eps, 1.25)) / (2 * eps)
+	if math.Abs(vx-ex) > 1e-12 || math.Abs(vy-ey) > 1e-12 {
+		t.Errorf("got (%.4f,%.4f), expected (%.4f,%.4f)", vx, vy, ex, ey)
+	}
+}
+
+// costs about four BenchmarkSimplex per octave
+func BenchmarkMultiOctaveCurl2(b *testing.B) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	for _, octaves := range []int{1, 4} {
+		b.Run(fmt.Sprintf("%doct", octaves), func(b *testing.B) {
+			x := 0.001
+			y := 0.0001
+			for i := 0; i < b.N; i++ {
+				n.MultiOctaveCurl2(x, y, octaves, 2, 0.5, 1e-4)
+				x += 0.00000011
+				y += 0.00000012
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package simplex
+
+import (
+	"math"
+)
+
+// noise2Deriv returns Noise2(x, y) along with its analytical gradient.
+// Each corner contributes t^4 (g.d) where t = 0.5-|d|^2, whose
+// derivative is t^4 g - 8 t^3 (g.d) d.
+func (s *Simplex) noise2Deriv(x, y float64) (n, dnx, dny float64) {
+	if !finite(x) || !finite(y) {
+		return math.NaN(), math.NaN(), math.NaN()
+	}
+	var i, j int
+	var x0, y0 float64
+	if math.Abs(x) < largeCoord && math.Abs(y) < largeCoord {
+		h := (x + y) * F2
+		i = fastfloor(x + h)
+		j = fastfloor(y + h)
+		t := float64(i+j) * G2
+		x0 = x - (float64(i) - t)
+		y0 = y - (float64(j) - t)
+	} else {
+		cell, d := skew(&[4]float64{x, y}, 2, F2, G2)
+		i, j = cell[0], cell[1]
+		x0, y0 = d[0], d[1]
+	}
+
+	i1, j1 := 0, 1
+	if x0 > y0 {
+		i1, j1 = 1, 0
+	}
+	corner := func(gi int, dx, dy float64) (float64, float64, float64) {
+		t := 0.5 - dx*dx - dy*dy
+		if t < 0 {
+			return 0, 0, 0
+		}
+		g := g3[gi]
+		gd := g.dot(dx, dy)
+		t2 := t * t
+		t4 := t2 * t2
+		return t4 * gd, t4*g.dx - 8*t2*t*gd*dx, t4*g.dy - 8*t2*t*gd*dy
+	}
+
+	ii := i & 255
+	jj := j & 255
+	n0, nx0, ny0 := corner(s.getPermMod12(ii+s.getPerm(jj)), x0, y0)
+	n1, nx1, ny1 := corner(s.getPermMod12(ii+i1+s.getPerm(jj+j1)), x0-float64(i1)+G2, y0-float64(j1)+G2)
+	n2, nx2, ny2 := corner(s.getPermMod12(ii+1+s.getPerm(jj+1)), x0-1.0+2.0*G2, y0-1.0+2.0*G2)
+	scale := s.noise2Scale()
+	return scale * (n0 + n1 + n2), scale * (nx0 + nx1 + nx2), scale * (ny0 + ny1 + ny2)
+}
+
+// fbm2Deriv sums octaves like FBM2, also summing the gradient, and
+// shifts the sampling point of each octave (after scaling by its
+// frequency) by warp times the gradient of the octaves before it
+func (s *Simplex) fbm2Deriv(x, y float64, octaves int, lacunarity, persistence, warp float64) (n, dnx, dny float64) {
+	var sum, total float64
+	f := 1.0
+	a := 1.0
+	for i := 0; i < octaves; i++ {
+		var wx, wy float64
+		if total > 0 {
+			wx = warp * dnx / total
+			wy = warp * dny / total
+		}
+		v, vx, vy := s.noise2Deriv(x*f+wx, y*f+wy)
+		sum += a * v
+		dnx += a * f * vx
+		dny += a * f * vy
+		total += a
+		f *= lacunarity
+		a *= persistence
+	}
+	if total == 0 {
+		return 0, 0, 0
+	}
+	return sum / total, dnx / total, dny / total
+}
+
+// FBM2WithDerivatives returns FBM2(x, y, octaves, lacunarity,
+// persistence) along with its gradient.  Each octave's gradient is
+// scaled by its amplitude and by its frequency (the chain rule for
+// sampling at f times the point), and the sum is divided by the total
+// amplitude just like the value.  This is what Swiss and Jordan noise
+// build on.
+func (s *Simplex) FBM2WithDerivatives(x, y float64, octaves int, lacunarity, persistence float64) (n, dnx, dny float64) {
+	return s.fbm2Deriv(x, y, octaves, lacunarity, persistence, 0)
+}
+
+// FBMWarp2 is like FBM2, but each octave is sampled at a point pushed
+// along the gradient of the octaves before it (as FBM2WithDerivatives
+// would compute it), by warpStrength times the gradient in that
+// octave's own units.  Steep places get stretched, which gives an
+// eroded, swirly look.  The gradient of the higher octaves is steep, so
+// warpStrength is usually small (around 0.1).
+func (s *Simplex) FBMWarp2(x, y float64, octaves int, warpStrength, lacunarity, persistence float64) float64 {
+	n, _, _ := s.fbm2Deriv(x, y, octaves, lacunarity, persistence, warpStrength)
+	return n
+}
+
+// Ridged2WithDerivatives returns Ridged2(x, y, octaves, lacunarity,
+// persistence, offset, gain) along with its gradient.  Where the noise
+// of an octave is exactly 0 the fold has no derivative; the gradient
+// of |noise| is taken to be 0 there.
+func (s *Simplex) Ridged2WithDerivatives(x, y float64, octaves int, lacunarity, persistence, offset, gain float64) (n, dnx, dny float64) {
+	var sum, total float64
+	f := 1.0
+	a := 1.0
+	// the weight carried over from the previous octave, and its gradient
+	weight, wx, wy := 1.0, 0.0, 0.0
+	for i := 0; i < octaves; i++ {
+		v, vx, vy := s.noise2Deriv(x*f, y*f)
+		var sign float64
+		if v > 0 {
+			sign = 1
+		} else if v < 0 {
+			sign = -1
+		}
+		r := offset - math.Abs(v)
+		// d(offset-|v|)/dx = -sign(v) dv/dx, and dv/dx picks up f
+		rx := -sign * f * vx
+		ry := -sign * f * vy
+		signal := r * r * weight
+		sx := 2*r*rx*weight + r*r*wx
+		sy := 2*r*ry*weight + r*r*wy
+
+		if w := signal * gain; w <= 0 {
+			weight, wx, wy = 0, 0, 0
+		} else if w >= 1 {
+			weight, wx, wy = 1, 0, 0
+		} else {
+			weight, wx, wy = w, gain*sx, gain*sy
+		}
+
+		sum += a * signal
+		dnx += a * sx
+		dny += a * sy
+		total += a
+		f *= lacunarity
+		a *= persistence
+	}
+	if total == 0 {
+		return 0, 0, 0
+	}
+	return sum / total, dnx / total, dny / total
+}
@@ -0,0 +1,133 @@
+package simplex
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestNoise2Deriv(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	const e = 1e-6
+	for i := 0; i < 10000; i++ {
+		x := r.Float64() * 100
+		y := r.Float64() * 100
+		v, dx, dy := n.noise2Deriv(x, y)
+		if a := n.Noise2(x, y); v != a {
+			t.Fatalf("Got %.4f, expected %.4f", v, a)
+		}
+		fx := (n.Noise2(x+e, y) - n.Noise2(x-e, y)) / (2 * e)
+		fy := (n.Noise2(x, y+e) - n.Noise2(x, y-e)) / (2 * e)
+		if math.Abs(dx-fx) > 1e-5 || math.Abs(dy-fy) > 1e-5 {
+			t.Fatalf("Got gradient (%.6f,%.6f) at (%g,%g), expected (%.6f,%.6f)", dx, dy, x, y, fx, fy)
+		}
+	}
+}
+
+func TestFBM2WithDerivatives(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	const e = 1e-6
+	for _, c := range []struct {
+		octaves                 int
+		lacunarity, persistence float64
+	}{
+		{1, 2, 0.5},
+		{5, 2, 0.5},
+		{8, 1.9, 0.6},
+		{4, 3, 0.3},
+	} {
+		for i := 0; i < 1000; i++ {
+			x := r.Float64() * 100
+			y := r.Float64() * 100
+			v, dx, dy := n.FBM2WithDerivatives(x, y, c.octaves, c.lacunarity, c.persistence)
+			if a := n.FBM2(x, y, c.octaves, c.lacunarity, c.persistence); math.Abs(v-a) > 1e-12 {
+				t.Fatalf("Got %.4f, expected %.4f", v, a)
+			}
+			fbm := func(x, y float64) float64 { return n.FBM2(x, y, c.octaves, c.lacunarity, c.persistence) }
+			fx := (fbm(x+e, y) - fbm(x-e, y)) / (2 * e)
+			fy := (fbm(x, y+e) - fbm(x, y-e)) / (2 * e)
+			// the top octave's frequency magnifies the finite difference error
+			tol := 1e-5 * math.Pow(c.lacunarity, float64(c.octaves-1))
+			if math.Abs(dx-fx) > tol || math.Abs(dy-fy) > tol {
+				t.Fatalf("Got gradient (%.6f,%.6f) at (%g,%g) with %d octaves, expected (%.6f,%.6f)", dx, dy, x, y, c.octaves, fx, fy)
+			}
+		}
+	}
+}
+
+func TestRidged2WithDerivatives(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	const e = 1e-7
+	ridged := func(x, y float64) float64 { return n.Ridged2(x, y, 5, 2, 0.5, 1, 2) }
+	var bad int
+	for i := 0; i < 10000; i++ {
+		x := r.Float64() * 100
+		y := r.Float64() * 100
+		v, dx, dy := n.Ridged2WithDerivatives(x, y, 5, 2, 0.5, 1, 2)
+		if a := ridged(x, y); math.Abs(v-a) > 1e-12 {
+			t.Fatalf("Got %.4f, expected %.4f", v, a)
+		}
+		fx := (ridged(x+e, y) - ridged(x-e, y)) / (2 * e)
+		fy := (ridged(x, y+e) - ridged(x, y-e)) / (2 * e)
+		if math.Abs(dx-fx) > 1e-4 || math.Abs(dy-fy) > 1e-4 {
+			bad++
+		}
+	}
+	// the difference straddles a crease (a zero of some octave, or the
+	// weight hitting its clamp) at only a handful of points
+	if bad > 10 {
+		t.Errorf("Got %d gradients out of 10000 that disagree with finite differences, expected at most 10", bad)
+	}
+}
+
+// gradientVariance returns the variance of the (finite difference)
+// gradient magnitude of f over a patch
+func gradientVariance(f func(x, y float64) float64) float64 {
+	r := rand.New(rand.NewSource(101))
+	const e = 1e-5
+	var g []float64
+	for i := 0; i < 10000; i++ {
+		x := r.Float64() * 100
+		y := r.Float64() * 100
+		gx := (f(x+e, y) - f(x-e, y)) / (2 * e)
+		gy := (f(x, y+e) - f(x, y-e)) / (2 * e)
+		g = append(g, math.Hypot(gx, gy))
+	}
+	var mean, v float64
+	for _, a := range g {
+		mean += a
+	}
+	mean /= float64(len(g))
+	for _, a := range g {
+		v += (a - mean) * (a - mean)
+	}
+	return v / float64(len(g))
+}
+
+func TestFBMWarp2(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	for i := 0; i < 10000; i++ {
+		x := r.Float64() * 100
+		y := r.Float64() * 100
+		if a, b := n.FBMWarp2(x, y, 5, 0, 2, 0.5), n.FBM2(x, y, 5, 2, 0.5); a != b {
+			t.Fatalf("Got %.4f at (%g,%g), expected %.4f", a, x, y, b)
+		}
+		if a := n.FBMWarp2(x, y, 5, 0.1, 2, 0.5); a < -1 || a > 1 {
+			t.Fatalf("Got %.4f at (%g,%g), expected value in [-1,1]", a, x, y)
+		}
+	}
+
+	plain := gradientVariance(func(x, y float64) float64 { return n.FBMWarp2(x, y, 5, 0, 2, 0.5) })
+	warped := gradientVariance(func(x, y float64) float64 { return n.FBMWarp2(x, y, 5, 0.1, 2, 0.5) })
+	if warped < 1.2*plain {
+		t.Errorf("Got gradient variance %.4f with warping, expected well above %.4f", warped, plain)
+	}
+}
@@ -0,0 +1,138 @@
+package simplex
+
+import (
+	"math"
+)
+
+// FBM2 sums octaves of Noise2 (fractional Brownian motion).  Each
+// octave is sampled at lacunarity times the frequency and persistence
+// times the amplitude of the previous one.  The sum is divided by the
+// total amplitude so the result stays in [-1,1].
+func (s *Simplex) FBM2(x, y float64, octaves int, lacunarity, persistence float64) float64 {
+	var sum, total float64
+	f := 1.0
+	a := 1.0
+	for i := 0; i < octaves; i++ {
+		sum += a * s.Noise2(x*f, y*f)
+		total += a
+		f *= lacunarity
+		a *= persistence
+	}
+	if total == 0 {
+		return 0
+	}
+	return sum / total
+}
+
+// FBM3 is the 3D version of FBM2
+func (s *Simplex) FBM3(x, y, z float64, octaves int, lacunarity, persistence float64) float64 {
+	var sum, total float64
+	f := 1.0
+	a := 1.0
+	for i := 0; i < octaves; i++ {
+		sum += a * s.Noise3(x*f, y*f, z*f)
+		total += a
+		f *= lacunarity
+		a *= persistence
+	}
+	if total == 0 {
+		return 0
+	}
+	return sum / total
+}
+
+// Turbulence2 is like FBM2, but sums the absolute value of each
+// octave, so the result is in [0,1] and has creases where the noise
+// crosses zero
+func (s *Simplex) Turbulence2(x, y float64, octaves int, lacunarity, persistence float64) float64 {
+	var sum, total float64
+	f := 1.0
+	a := 1.0
+	for i := 0; i < octaves; i++ {
+		sum += a * math.Abs(s.Noise2(x*f, y*f))
+		total += a
+		f *= lacunarity
+		a *= persistence
+	}
+	if total == 0 {
+		return 0
+	}
+	return sum / total
+}
+
+// Turbulence3 is the 3D version of Turbulence2
+func (s *Simplex) Turbulence3(x, y, z float64, octaves int, lacunarity, persistence float64) float64 {
+	var sum, total float64
+	f := 1.0
+	a := 1.0
+	for i := 0; i < octaves; i++ {
+		sum += a * math.Abs(s.Noise3(x*f, y*f, z*f))
+		total += a
+		f *= lacunarity
+		a *= persistence
+	}
+	if total == 0 {
+		return 0
+	}
+	return sum / total
+}
+
+// Noise2Scaled returns Noise2 sampled at frequency times (x, y)
+func (s *Simplex) Noise2Scaled(x, y, frequency float64) float64 {
+	return s.Noise2(x*frequency, y*frequency)
+}
+
+// Mul2 is a shorter name for Noise2Scaled, which scales both
+// coordinates together (scaling just one is an easy slip in an octave
+// loop)
+func (s *Simplex) Mul2(x, y, frequency float64) float64 {
+	return s.Noise2Scaled(x, y, frequency)
+}
+
+// Ridged2 is Musgrave's ridged multifractal.  Each octave is folded
+// into a ridge, (offset-|noise|)^2, and weighted by gain times the
+// octave before it, so detail piles up along the ridges and the valleys
+// stay smooth.  The sum is divided by the total amplitude, so the result
+// is in [0,offset^2] when offset is at least 1.  An offset of 1 and a
+// gain of 2 are the usual choices.
+func (s *Simplex) Ridged2(x, y float64, octaves int, lacunarity, persistence, offset, gain float64) float64 {
+	var sum, total float64
+	f := 1.0
+	a := 1.0
+	weight := 1.0
+	for i := 0; i < octaves; i++ {
+		signal := offset - math.Abs(s.Noise2(x*f, y*f))
+		signal *= signal * weight
+		weight = math.Max(0, math.Min(1, signal*gain))
+		sum += a * signal
+		total += a
+		f *= lacunarity
+		a *= persistence
+	}
+	if total == 0 {
+		return 0
+	}
+	return sum / total
+}
+
+// BandPassNoise2 is FBM2 with only the octaves whose frequency
+// (lacunarity^i) is in [freqMin, freqMax].  The sum is still divided by
+// the total amplitude of all the octaves, so bands that split the
+// octaves between them add up to FBM2.
+func (s *Simplex) BandPassNoise2(x, y float64, octaves int, lacunarity, persistence, freqMin, freqMax float64) float64 {
+	var sum, total float64
+	f := 1.0
+	a := 1.0
+	for i := 0; i < octaves; i++ {
+		if f >= freqMin && f <= freqMax {
+			sum += a * s.Noise2(x*f, y*f)
+		}
+		total += a
+		f *= lacunarity
+		a *= persistence
+	}
+	if total == 0 {
+		return 0
+	}
+	return sum / total
+}
@@ -0,0 +1,165 @@
+package simplex
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestFBM2(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	// a single octave is just plain noise
+	a := n.FBM2(0, 1.25, 1, 2, 0.5)
+	a0 := n.Noise2(0, 1.25)
+	if a != a0 {
+		t.Errorf("Got %.4f, expected %.4f", a, a0)
+	}
+
+	for i := 0; i < 100000; i++ {
+		x := r.Float64() * 10
+		y := r.Float64() * 10
+		a := n.FBM2(x, y, 6, 2, 0.5)
+		if a < -1 || a > 1 {
+			t.Fatalf("got %.4f at (%g, %g), expected value in [-1,1]", a, x, y)
+		}
+	}
+}
+
+func TestFBM3(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	a := n.FBM3(0, 1.25, 0.5, 1, 2, 0.5)
+	a0 := n.Noise3(0, 1.25, 0.5)
+	if a != a0 {
+		t.Errorf("Got %.4f, expected %.4f", a, a0)
+	}
+
+	for i := 0; i < 100000; i++ {
+		x := r.Float64() * 10
+		y := r.Float64() * 10
+		z := r.Float64() * 10
+		a := n.FBM3(x, y, z, 6, 2, 0.5)
+		if a < -1 || a > 1 {
+			t.Fatalf("got %.4f at (%g, %g, %g), expected value in [-1,1]", a, x, y, z)
+		}
+	}
+	if math.IsNaN(n.FBM3(1, 2, 3, 0, 2, 0.5)) {
+		t.Errorf("zero octaves should not produce NaN")
+	}
+}
+
+func TestRidged2(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	var peak float64
+	for i := 0; i < 100000; i++ {
+		x := r.Float64() * 100
+		y := r.Float64() * 100
+		a := n.Ridged2(x, y, 6, 2, 0.5, 1, 2)
+		if a < 0 || a > 1 {
+			t.Fatalf("Got %.4f at (%g,%g), expected value in [0,1]", a, x, y)
+		}
+		peak = math.Max(peak, a)
+	}
+	if peak < 0.5 {
+		t.Errorf("Got peak %.4f, expected ridges reaching well above 0.5", peak)
+	}
+}
+
+func TestBandPassNoise2(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	for i := 0; i < 10000; i++ {
+		x := r.Float64() * 100
+		y := r.Float64() * 100
+		low := n.BandPassNoise2(x, y, 8, 2, 0.5, 1, 8)
+		high := n.BandPassNoise2(x, y, 8, 2, 0.5, 16, 128)
+		if full := n.FBM2(x, y, 8, 2, 0.5); math.Abs(low+high-full) > 1e-12 {
+			t.Fatalf("Got %.4f + %.4f at (%g,%g), expected them to add up to %.4f", low, high, x, y, full)
+		}
+	}
+
+	// roughness is the typical change over a small step relative to the
+	// typical value, which grows with the frequency of the band
+	roughness := func(freqMin, freqMax float64) float64 {
+		var step, size float64
+		for i := 0; i < 10000; i++ {
+			x := r.Float64() * 100
+			y := r.Float64() * 100
+			a := n.BandPassNoise2(x, y, 8, 2, 0.5, freqMin, freqMax)
+			step += math.Abs(n.BandPassNoise2(x+0.01, y, 8, 2, 0.5, freqMin, freqMax) - a)
+			size += math.Abs(a)
+		}
+		return step / size
+	}
+	if low, high := roughness(1, 2), roughness(32, 128); high < 10*low {
+		t.Errorf("Got roughness %.4f for the high band, expected well above %.4f for the low band", high, low)
+	}
+}
+
+func TestNoise2Scaled(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	for i := 0; i < 1000; i++ {
+		x := r.Float64() * 10
+		y := r.Float64() * 10
+		if a, b := n.Noise2Scaled(x, y, 1), n.Noise2(x, y); a != b {
+			t.Fatalf("Got %.4f, expected %.4f", a, b)
+		}
+		if a, b := n.Noise2Scaled(x, y, 2), n.Noise2(2*x, 2*y); a != b {
+			t.Fatalf("Got %.4f, expected %.4f", a, b)
+		}
+		if a, b := n.Mul2(x, y, 1), n.Noise2(x, y); a != b {
+			t.Fatalf("Got %.4f, expected %.4f", a, b)
+		}
+		if a, b := n.Mul2(x, y, 2), n.Noise2(2*x, 2*y); a != b {
+			t.Fatalf("Got %.4f, expected %.4f", a, b)
+		}
+	}
+}
+
+func TestTurbulence(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	if a, b := n.Turbulence2(0, 1.25, 1, 2, 0.5), math.Abs(n.Noise2(0, 1.25)); a != b {
+		t.Errorf("Got %.4f, expected %.4f", a, b)
+	}
+	for i := 0; i < 100000; i++ {
+		x := r.Float64() * 10
+		y := r.Float64() * 10
+		z := r.Float64() * 10
+		if a := n.Turbulence2(x, y, 6, 2, 0.5); a < 0 || a > 1 {
+			t.Fatalf("got %.4f at (%g, %g), expected value in [0,1]", a, x, y)
+		}
+		if a := n.Turbulence3(x, y, z, 6, 2, 0.5); a < 0 || a > 1 {
+			t.Fatalf("got %.4f at (%g, %g, %g), expected value in [0,1]", a, x, y, z)
+		}
+	}
+}
+
+// each octave costs about one BenchmarkSimplex, with no allocations
+func BenchmarkFBM2(b *testing.B) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	for _, octaves := range []int{4, 8} {
+		b.Run(fmt.Sprintf("%doct", octaves), func(b *testing.B) {
+			b.ReportAllocs()
+			x := 0.001
+			y := 0.0001
+			for i := 0; i < b.N; i++ {
+				n.FBM2(x, y, octaves, 2, 0.5)
+				x += 0.00000011
+				y += 0.00000012
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package simplex
+
+import (
+	"math"
+)
+
+// GaborNoise2 returns sparse convolution noise at (x, y) made of Gabor
+// kernels: Gaussians of width 1/bandwidth times a cosine wave with the
+// given frequency, running along the orientation angle (in radians).
+// Unlike gradient noise, its power spectrum is concentrated around that
+// frequency and direction.  The plane is divided into cells the size of
+// a kernel, each holding about impulses kernels per unit area (at least
+// one), placed and weighted by a small generator seeded by hashing the
+// cell through the permutation table.  The result is not bounded, but
+// it has about the same spread as Noise2.  Each sample sums the kernels
+// of nine cells, about 9*impulses/bandwidth^2 of them, so low
+// bandwidths with many impulses get expensive.
+func (s *Simplex) GaborNoise2(x, y, frequency, orientation, bandwidth float64, impulses int) float64 {
+	if bandwidth <= 0 || impulses <= 0 {
+		return 0
+	}
+	// the kernel is below 5% of its peak beyond this radius
+	r := math.Sqrt(math.Log(20)/math.Pi) / bandwidth
+	per := int(math.Round(float64(impulses) * r * r))
+	if per < 1 {
+		per = 1
+	}
+	sn, cs := math.Sincos(orientation)
+	cx, cy := x/r, y/r
+	i0 := fastfloor(cx)
+	j0 := fastfloor(cy)
+
+	var sum float64
+	for j := j0 - 1; j <= j0+1; j++ {
+		for i := i0 - 1; i <= i0+1; i++ {
+			// the impulses of a cell come from a small LCG seeded by
+			// hashing the cell, so they don't repeat however many there
+			// are
+			state := uint32(s.getPerm(i+s.getPerm(j)))<<16 |
+				uint32(s.getPerm(j+s.getPerm(i+1)))<<8 |
+				uint32(s.getPerm(i+s.getPerm(j+1)))
+			next := func() float64 {
+				state = state*1664525 + 1013904223
+				return float64(state>>8) / (1 << 24)
+			}
+			for k := 0; k < per; k++ {
+				u := (float64(i)+next())*r - x
+				v := (float64(j)+next())*r - y
+				weight := next()
+				d2 := u*u + v*v
+				if d2 > r*r {
+					continue
+				}
+				g := math.Exp(-math.Pi*bandwidth*bandwidth*d2) * math.Cos(2*math.Pi*frequency*(u*cs+v*sn))
+				if weight < 0.5 {
+					sum += g
+				} else {
+					sum -= g
+				}
+			}
+		}
+	}
+	// scale by the expected standard deviation of the sum
+	density := float64(per) / (r * r)
+	return sum * 0.44 / math.Sqrt(density/(4*bandwidth*bandwidth))
+}
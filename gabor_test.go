@@ -0,0 +1,82 @@
+package simplex
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestGaborNoise2(t *testing.T) {
+	n := New(rand.New(rand.NewSource(101)))
+
+	const freq, orient = 2.0, 0.5
+	if a, b := n.GaborNoise2(1.5, 2.5, freq, orient, 1, 16), New(rand.New(rand.NewSource(101))).GaborNoise2(1.5, 2.5, freq, orient, 1, 16); a != b {
+		t.Errorf("Got %.4f and %.4f for the same seed", a, b)
+	}
+
+	// sample along lines in the kernel direction and average the power
+	// spectra (up to 4 cycles per unit)
+	const N = 256
+	const step = 1.0 / 8
+	var power [N / 2]float64
+	c, s := math.Cos(orient), math.Sin(orient)
+	for line := 0; line < 20; line++ {
+		var v [N]float64
+		for k := range v {
+			d := float64(k) * step
+			v[k] = n.GaborNoise2(d*c-float64(line)*7*s, d*s+float64(line)*7*c, freq, orient, 1, 16)
+		}
+		for f := 1; f < N/2; f++ {
+			var re, im float64
+			for k, a := range v {
+				re += a * math.Cos(2*math.Pi*float64(f*k)/N)
+				im -= a * math.Sin(2*math.Pi*float64(f*k)/N)
+			}
+			power[f] += re*re + im*im
+		}
+	}
+	var total, near float64
+	peak := 1
+	for f := 1; f < N/2; f++ {
+		total += power[f]
+		cycles := float64(f) / (N * step)
+		if math.Abs(cycles-freq) <= freq/2 {
+			near += power[f]
+		}
+		if power[f] > power[peak] {
+			peak = f
+		}
+	}
+	if cycles := float64(peak) / (N * step); math.Abs(cycles-freq) > 0.5 {
+		t.Errorf("Got peak at %.4f cycles per unit, expected %.4f", cycles, freq)
+	}
+	if near < 0.7*total {
+		t.Errorf("Got %.4f of the power within %.4f of the frequency, expected most of it", near/total, freq/2)
+	}
+}
+
+func TestGaborNoise2Spread(t *testing.T) {
+	n := New(rand.New(rand.NewSource(101)))
+	r := rand.New(rand.NewSource(101))
+
+	// past 128 impulses per cell, the kernels used to repeat and pile
+	// up coherently
+	for _, c := range []struct {
+		bandwidth float64
+		impulses  int
+	}{
+		{1, 16},
+		{0.5, 64},
+		{0.5, 256},
+	} {
+		var sum2 float64
+		const samples = 2000
+		for i := 0; i < samples; i++ {
+			a := n.GaborNoise2(r.Float64()*500, r.Float64()*500, 2, 0.5, c.bandwidth, c.impulses)
+			sum2 += a * a
+		}
+		if std := math.Sqrt(sum2 / samples); math.Abs(std-0.439) > 0.05 {
+			t.Errorf("Got spread %.4f with bandwidth %g and %d impulses, expected about %.4f like Noise2", std, c.bandwidth, c.impulses, 0.439)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package simplex
+
+import (
+	"math"
+)
+
+// RockType identifies a kind of rock in a geological column
+type RockType int
+
+const (
+	Soil RockType = iota
+	Sandstone
+	Shale
+	Limestone
+	Granite
+	Basalt
+)
+
+var rockTypeNames = [...]string{
+	Soil:      "soil",
+	Sandstone: "sandstone",
+	Shale:     "shale",
+	Limestone: "limestone",
+	Granite:   "granite",
+	Basalt:    "basalt",
+}
+
+func (r RockType) String() string {
+	if r >= 0 && int(r) < len(rockTypeNames) {
+		return rockTypeNames[r]
+	}
+	return "unknown"
+}
+
+// GeologicalLayers3 returns the rock at depth z below (x, y).  The
+// ideal strata are horizontal, one unit thick, in the order given by
+// rockTypes (the last one extends all the way down).  Noise3 bends the
+// layer boundaries up and down by as much as disturbance units to
+// simulate folding.
+func GeologicalLayers3(s *Simplex, x, y, z float64, rockTypes []RockType, disturbance float64) RockType {
+	if len(rockTypes) == 0 {
+		return Soil
+	}
+	d := z + disturbance*s.Noise3(x*0.1, y*0.1, z*0.1)
+	i := int(math.Floor(d))
+	if i < 0 {
+		i = 0
+	} else if i >= len(rockTypes) {
+		i = len(rockTypes) - 1
+	}
+	return rockTypes[i]
+}
@@ -0,0 +1,41 @@
+package simplex
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestGeologicalLayers3(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+	strata := []RockType{Soil, Sandstone, Shale, Limestone, Granite}
+
+	// undisturbed layers are flat
+	for i := 0; i < 1000; i++ {
+		x := r.Float64() * 100
+		y := r.Float64() * 100
+		z := r.Float64() * 4
+		got := GeologicalLayers3(n, x, y, z, strata, 0)
+		if want := strata[int(z)]; got != want {
+			t.Fatalf("got %s at depth %g, expected %s", got, z, want)
+		}
+	}
+	if got := GeologicalLayers3(n, 0, 0, 100, strata, 0); got != Granite {
+		t.Errorf("got %s deep down, expected %s", got, Granite)
+	}
+
+	// disturbed layers are not
+	folded := 0
+	for x := 0.0; x < 100; x++ {
+		if GeologicalLayers3(n, x, 0, 2.5, strata, 2) != Shale {
+			folded++
+		}
+	}
+	if folded == 0 {
+		t.Errorf("disturbance did not fold the strata")
+	}
+
+	if s := Limestone.String(); s != "limestone" {
+		t.Errorf("got %q, expected %q", s, "limestone")
+	}
+}
@@ -0,0 +1,292 @@
+package simplex
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"math"
+	"sync"
+)
+
+// Grid2D is a grid of values, stored row-major
+type Grid2D struct {
+	Data          []float64
+	Width, Height int
+}
+
+// ErrGridSize is returned when combining grids of different sizes (and
+// FillGrid2 panics with it when given a grid of the wrong size)
+var ErrGridSize = errors.New("simplex: grid sizes differ")
+
+// NewGrid2D returns a width x height grid of zeros
+func NewGrid2D(width, height int) *Grid2D {
+	return &Grid2D{
+		Data:   make([]float64, width*height),
+		Width:  width,
+		Height: height,
+	}
+}
+
+func (g *Grid2D) At(x, y int) float64 {
+	return g.Data[y*g.Width+x]
+}
+
+func (g *Grid2D) Set(x, y int, v float64) {
+	g.Data[y*g.Width+x] = v
+}
+
+func (g *Grid2D) Clone() *Grid2D {
+	c := NewGrid2D(g.Width, g.Height)
+	copy(c.Data, g.Data)
+	return c
+}
+
+// Normalize returns a copy of the grid with its values stretched to
+// exactly cover [-1,1].  A constant grid becomes all zeros.
+func (g *Grid2D) Normalize() *Grid2D {
+	c := NewGrid2D(g.Width, g.Height)
+	normalize(c.Data, g.Data)
+	return c
+}
+
+// Statistics returns the smallest, largest and mean value of the grid
+// and the (population) standard deviation
+func (g *Grid2D) Statistics() (min, max, mean, stddev float64) {
+	return statistics(g.Data)
+}
+
+// normalize stores the values of src, stretched to cover [-1,1], in dst
+func normalize(dst, src []float64) {
+	lo, hi, _, _ := statistics(src)
+	if hi > lo {
+		for i, v := range src {
+			dst[i] = 2*(v-lo)/(hi-lo) - 1
+		}
+	}
+}
+
+// statistics computes the summary statistics of data in a single pass
+// (using Welford's running variance, which doesn't lose precision the
+// way summing squares does)
+func statistics(data []float64) (min, max, mean, stddev float64) {
+	if len(data) == 0 {
+		return 0, 0, 0, 0
+	}
+	min = math.Inf(1)
+	max = math.Inf(-1)
+	var m2 float64
+	for i, v := range data {
+		min = math.Min(min, v)
+		max = math.Max(max, v)
+		d := v - mean
+		mean += d / float64(i+1)
+		m2 += d * (v - mean)
+	}
+	return min, max, mean, math.Sqrt(m2 / float64(len(data)))
+}
+
+// Add returns the element-wise sum of two grids of the same size
+func (g *Grid2D) Add(other *Grid2D) (*Grid2D, error) {
+	if other.Width != g.Width || other.Height != g.Height {
+		return nil, ErrGridSize
+	}
+	out := NewGrid2D(g.Width, g.Height)
+	for i, v := range g.Data {
+		out.Data[i] = v + other.Data[i]
+	}
+	return out, nil
+}
+
+// Scale returns a copy of the grid with every value multiplied by factor
+func (g *Grid2D) Scale(factor float64) *Grid2D {
+	out := NewGrid2D(g.Width, g.Height)
+	for i, v := range g.Data {
+		out.Data[i] = v * factor
+	}
+	return out
+}
+
+// Blend returns g*(1-mask) + other*mask, element by element, with the
+// mask clamped to [0,1].  All three grids must be the same size.
+func (g *Grid2D) Blend(other, mask *Grid2D) (*Grid2D, error) {
+	if other.Width != g.Width || other.Height != g.Height || mask.Width != g.Width || mask.Height != g.Height {
+		return nil, ErrGridSize
+	}
+	out := NewGrid2D(g.Width, g.Height)
+	for i, v := range g.Data {
+		m := clamp01(mask.Data[i])
+		out.Data[i] = v*(1-m) + other.Data[i]*m
+	}
+	return out, nil
+}
+
+// Smooth returns a Gaussian blurred copy of the grid.  The kernel
+// reaches radius cells out (with a standard deviation of radius/2), and
+// is applied as a horizontal and then a vertical pass.  Cells beyond the
+// edges take the value of the nearest edge cell.
+func (g *Grid2D) Smooth(radius int) *Grid2D {
+	if radius <= 0 {
+		return g.Clone()
+	}
+	weights := make([]float64, 2*radius+1)
+	var total float64
+	sigma := float64(radius) / 2
+	for k := range weights {
+		d := float64(k - radius)
+		weights[k] = math.Exp(-d * d / (2 * sigma * sigma))
+		total += weights[k]
+	}
+	for k := range weights {
+		weights[k] /= total
+	}
+	clamp := func(i, n int) int {
+		if i < 0 {
+			return 0
+		}
+		if i >= n {
+			return n - 1
+		}
+		return i
+	}
+
+	tmp := NewGrid2D(g.Width, g.Height)
+	for y := 0; y < g.Height; y++ {
+		for x := 0; x < g.Width; x++ {
+			var sum float64
+			for k, w := range weights {
+				sum += w * g.At(clamp(x+k-radius, g.Width), y)
+			}
+			tmp.Set(x, y, sum)
+		}
+	}
+	out := NewGrid2D(g.Width, g.Height)
+	for y := 0; y < g.Height; y++ {
+		for x := 0; x < g.Width; x++ {
+			var sum float64
+			for k, w := range weights {
+				sum += w * tmp.At(x, clamp(y+k-radius, g.Height))
+			}
+			out.Set(x, y, sum)
+		}
+	}
+	return out
+}
+
+// ToGrayImage renders the grid as an image, with values in [-1,1]
+// mapped to black through white.  Values out of range are clamped.
+func (g *Grid2D) ToGrayImage() *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, g.Width, g.Height))
+	for y := 0; y < g.Height; y++ {
+		for x := 0; x < g.Width; x++ {
+			img.SetGray(x, y, color.Gray{Y: grayLevel(g.At(x, y))})
+		}
+	}
+	return img
+}
+
+// ReadHeightMapFromGray returns a grid the size of img, with pixel
+// values 0 through 255 mapped to -1 through 1, undoing ToGrayImage up to
+// its rounding to 8 bits
+func ReadHeightMapFromGray(img *image.Gray) *Grid2D {
+	b := img.Bounds()
+	g := NewGrid2D(b.Dx(), b.Dy())
+	for y := 0; y < g.Height; y++ {
+		for x := 0; x < g.Width; x++ {
+			g.Set(x, y, float64(img.GrayAt(b.Min.X+x, b.Min.Y+y).Y)/255*2-1)
+		}
+	}
+	return g
+}
+
+// FillGrid2 sets each value (x, y) of out to Noise2(offsetX+x*stepX,
+// offsetY+y*stepY) and returns it.  If out is nil, a new width x height
+// grid is allocated.  Otherwise out must be width x height; it panics
+// with ErrGridSize if it isn't.
+func (s *Simplex) FillGrid2(out *Grid2D, width, height int, offsetX, offsetY, stepX, stepY float64) *Grid2D {
+	out = gridFor(out, width, height)
+	for y := 0; y < out.Height; y++ {
+		s.fillRow2(out.Data[y*out.Width:(y+1)*out.Width], offsetX, offsetY+float64(y)*stepY, stepX)
+	}
+	return out
+}
+
+// gridFor returns out, or a new width x height grid if out is nil,
+// panicking if out is the wrong size
+func gridFor(out *Grid2D, width, height int) *Grid2D {
+	if out == nil {
+		return NewGrid2D(width, height)
+	}
+	if out.Width != width || out.Height != height {
+		panic(ErrGridSize)
+	}
+	return out
+}
+
+// NoiseFunc2 is any 2D noise field, such as the Noise2 method of a
+// Simplex or a closure around FBM2
+type NoiseFunc2 func(x, y float64) float64
+
+// AsNoiseFunc2 returns s.Noise2 as a NoiseFunc2
+func (s *Simplex) AsNoiseFunc2() NoiseFunc2 {
+	return func(x, y float64) float64 {
+		return s.Noise2(x, y)
+	}
+}
+
+// Map2 returns a width x height grid holding fn(ox+x*dx, oy+y*dy) at
+// each (x, y)
+func Map2(fn NoiseFunc2, width, height int, ox, oy, dx, dy float64) *Grid2D {
+	g := NewGrid2D(width, height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			g.Set(x, y, fn(ox+float64(x)*dx, oy+float64(y)*dy))
+		}
+	}
+	return g
+}
+
+func (s *Simplex) fillRow2(row []float64, x0, y, stepX float64) {
+	for col := range row {
+		row[col] = s.Noise2(x0+float64(col)*stepX, y)
+	}
+}
+
+// ConcurrentFillGrid2 sets out[row][col] to Noise2(offsetX+col*stepX,
+// offsetY+row*stepY), spreading the rows over the given number of
+// goroutines (at least one).  Worker k fills rows k, k+workers,
+// k+2*workers, and so on.  The rows may have different lengths.
+func (s *Simplex) ConcurrentFillGrid2(out [][]float64, offsetX, offsetY, stepX, stepY float64, workers int) {
+	s.concurrentRows(len(out), workers, func(row int) {
+		s.fillRow2(out[row], offsetX, offsetY+float64(row)*stepY, stepX)
+	})
+}
+
+// ConcurrentFillGrid2D is ConcurrentFillGrid2 for a Grid2D, and is
+// otherwise like FillGrid2: a nil out allocates a width x height grid,
+// and a non-nil out must be width x height.
+func (s *Simplex) ConcurrentFillGrid2D(out *Grid2D, width, height int, offsetX, offsetY, stepX, stepY float64, workers int) *Grid2D {
+	out = gridFor(out, width, height)
+	s.concurrentRows(out.Height, workers, func(y int) {
+		s.fillRow2(out.Data[y*out.Width:(y+1)*out.Width], offsetX, offsetY+float64(y)*stepY, stepX)
+	})
+	return out
+}
+
+// concurrentRows calls fill for each of rows rows, striped over workers
+// goroutines
+func (s *Simplex) concurrentRows(rows, workers int, fill func(row int)) {
+	if workers < 1 {
+		workers = 1
+	}
+	var wg sync.WaitGroup
+	for k := 0; k < workers; k++ {
+		wg.Add(1)
+		go func(k int) {
+			defer wg.Done()
+			for row := k; row < rows; row += workers {
+				fill(row)
+			}
+		}(k)
+	}
+	wg.Wait()
+}
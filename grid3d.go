@@ -0,0 +1,47 @@
+package simplex
+
+// Grid3D is a volume of values, stored x fastest, then y, then z
+type Grid3D struct {
+	Data                 []float64
+	Width, Height, Depth int
+}
+
+// NewGrid3D returns a width x height x depth volume of zeros
+func NewGrid3D(width, height, depth int) *Grid3D {
+	return &Grid3D{
+		Data:   make([]float64, width*height*depth),
+		Width:  width,
+		Height: height,
+		Depth:  depth,
+	}
+}
+
+func (g *Grid3D) At(x, y, z int) float64 {
+	return g.Data[x+g.Width*(y+g.Height*z)]
+}
+
+func (g *Grid3D) Set(x, y, z int, v float64) {
+	g.Data[x+g.Width*(y+g.Height*z)] = v
+}
+
+// Slice returns a copy of layer z
+func (g *Grid3D) Slice(z int) *Grid2D {
+	s := NewGrid2D(g.Width, g.Height)
+	n := g.Width * g.Height
+	copy(s.Data, g.Data[z*n:(z+1)*n])
+	return s
+}
+
+// Normalize returns a copy of the volume with its values stretched to
+// exactly cover [-1,1].  A constant volume becomes all zeros.
+func (g *Grid3D) Normalize() *Grid3D {
+	c := NewGrid3D(g.Width, g.Height, g.Depth)
+	normalize(c.Data, g.Data)
+	return c
+}
+
+// Statistics returns the smallest, largest and mean value of the volume
+// and the (population) standard deviation
+func (g *Grid3D) Statistics() (min, max, mean, stddev float64) {
+	return statistics(g.Data)
+}
@@ -0,0 +1,44 @@
+package simplex
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestGrid3D(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	g := NewGrid3D(6, 5, 4)
+	for z := 0; z < 4; z++ {
+		for y := 0; y < 5; y++ {
+			for x := 0; x < 6; x++ {
+				g.Set(x, y, z, n.Noise3(float64(x)*0.3, float64(y)*0.3, float64(z)*0.3))
+			}
+		}
+	}
+	for z := 0; z < 4; z++ {
+		s := g.Slice(z)
+		if s.Width != g.Width || s.Height != g.Height {
+			t.Fatalf("Got %dx%d slice, expected %dx%d", s.Width, s.Height, g.Width, g.Height)
+		}
+		for y := 0; y < 5; y++ {
+			for x := 0; x < 6; x++ {
+				if a, b := g.At(x, y, z), g.Data[x+g.Width*(y+g.Height*z)]; a != b {
+					t.Fatalf("Got %.4f at (%d,%d,%d), expected %.4f", a, x, y, z, b)
+				}
+				if a, b := s.At(x, y), g.At(x, y, z); a != b {
+					t.Fatalf("Got %.4f in slice %d at (%d,%d), expected %.4f", a, z, x, y, b)
+				}
+			}
+		}
+	}
+
+	lo, hi, _, _ := g.Normalize().Statistics()
+	if lo != -1 || hi != 1 {
+		t.Errorf("Got range [%.4f,%.4f] after Normalize, expected [-1,1]", lo, hi)
+	}
+	if lo, _, _, _ := g.Statistics(); lo == -1 {
+		t.Errorf("Normalize changed the original volume")
+	}
+}
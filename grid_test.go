@@ -0,0 +1,340 @@
+package simplex
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestFillGrid2(t *testing.T) {
+	n := New(rand.New(rand.NewSource(101)))
+
+	g := n.FillGrid2(nil, 30, 20, 0.5, -2, 0.1, 0.15)
+	if g.Width != 30 || g.Height != 20 || len(g.Data) != 600 {
+		t.Fatalf("got %dx%d grid with %d values, expected 30x20", g.Width, g.Height, len(g.Data))
+	}
+	for y := 0; y < g.Height; y++ {
+		for x := 0; x < g.Width; x++ {
+			if v, expect := g.At(x, y), n.Noise2(0.5+float64(x)*0.1, -2+float64(y)*0.15); v != expect {
+				t.Fatalf("Got %.4f at (%d,%d), expected %.4f", v, x, y, expect)
+			}
+		}
+	}
+
+	out := NewGrid2D(30, 20)
+	if g2 := n.FillGrid2(out, 30, 20, 0.5, -2, 0.1, 0.15); g2 != out {
+		t.Errorf("FillGrid2 did not fill the grid it was given")
+	}
+	for i := range out.Data {
+		if out.Data[i] != g.Data[i] {
+			t.Fatalf("Got %.4f at %d, expected %.4f", out.Data[i], i, g.Data[i])
+		}
+	}
+
+	for _, size := range [][2]int{{0, 0}, {20, 30}, {30, 21}} {
+		expectPanic(t, ErrGridSize, func() { n.FillGrid2(out, size[0], size[1], 0.5, -2, 0.1, 0.15) })
+		expectPanic(t, ErrGridSize, func() { n.ConcurrentFillGrid2D(out, size[0], size[1], 0.5, -2, 0.1, 0.15, 4) })
+	}
+}
+
+// expectPanic checks that f panics with err
+func expectPanic(t *testing.T, err error, f func()) {
+	t.Helper()
+	defer func() {
+		if r := recover(); r != err {
+			t.Errorf("got panic %v, expected %v", r, err)
+		}
+	}()
+	f()
+}
+
+func newGrid(width, height int) [][]float64 {
+	out := make([][]float64, height)
+	for i := range out {
+		out[i] = make([]float64, width)
+	}
+	return out
+}
+
+func TestReadHeightMapFromGray(t *testing.T) {
+	n := New(rand.New(rand.NewSource(101)))
+
+	g := ReadHeightMapFromGray(n.NewGrayImage(50, 40, 0, 0, 0.05, 0.05))
+	if g.Width != 50 || g.Height != 40 {
+		t.Fatalf("Got %dx%d grid, expected 50x40", g.Width, g.Height)
+	}
+	for y := 0; y < 40; y++ {
+		for x := 0; x < 50; x++ {
+			expect := n.Noise2(float64(x)*0.05, float64(y)*0.05)
+			if a := g.At(x, y); math.Abs(a-expect) > 1.0/255 {
+				t.Fatalf("Got %.4f at (%d,%d), expected %.4f", a, x, y, expect)
+			}
+		}
+	}
+
+	// a subimage keeps its own origin
+	img := image.NewGray(image.Rect(3, 4, 6, 6))
+	img.SetGray(3, 4, color.Gray{Y: 255})
+	if g := ReadHeightMapFromGray(img); g.Width != 3 || g.Height != 2 || g.At(0, 0) != 1 || g.At(1, 0) != -1 {
+		t.Errorf("Got %v, expected a 3x2 grid starting with 1, -1", g)
+	}
+}
+
+func TestMap2(t *testing.T) {
+	n := New(rand.New(rand.NewSource(101)))
+
+	a := Map2(n.AsNoiseFunc2(), 30, 20, 0.5, -2, 0.1, 0.15)
+	b := n.FillGrid2(nil, 30, 20, 0.5, -2, 0.1, 0.15)
+	if a.Width != b.Width || a.Height != b.Height {
+		t.Fatalf("Got %dx%d grid, expected %dx%d", a.Width, a.Height, b.Width, b.Height)
+	}
+	for i := range a.Data {
+		if a.Data[i] != b.Data[i] {
+			t.Fatalf("Got %.4f at %d, expected %.4f", a.Data[i], i, b.Data[i])
+		}
+	}
+
+	fbm := Map2(func(x, y float64) float64 { return n.FBM2(x, y, 4, 2, 0.5) }, 30, 20, 0.5, -2, 0.1, 0.15)
+	for y := 0; y < 20; y++ {
+		for x := 0; x < 30; x++ {
+			if v, expect := fbm.At(x, y), n.FBM2(0.5+float64(x)*0.1, -2+float64(y)*0.15, 4, 2, 0.5); v != expect {
+				t.Fatalf("Got %.4f at (%d,%d), expected %.4f", v, x, y, expect)
+			}
+		}
+	}
+}
+
+func TestGrid2D(t *testing.T) {
+	n := New(rand.New(rand.NewSource(101)))
+
+	g := NewGrid2D(7, 5)
+	g.Set(3, 4, 0.25)
+	g.Set(6, 0, -0.5)
+	if a := g.At(3, 4); a != 0.25 {
+		t.Errorf("Got %.4f, expected %.4f", a, 0.25)
+	}
+	if a := g.Data[6]; a != -0.5 {
+		t.Errorf("Got %.4f at index 6, expected %.4f", a, -0.5)
+	}
+
+	c := g.Clone()
+	c.Set(3, 4, 1)
+	if a := g.At(3, 4); a != 0.25 {
+		t.Errorf("Changing a clone changed the original to %.4f", a)
+	}
+
+	noise := n.FillGrid2(nil, 64, 48, 0, 0, 0.05, 0.05)
+	norm := noise.Normalize()
+	lo, hi, _, _ := norm.Statistics()
+	if lo != -1 || hi != 1 {
+		t.Errorf("Got range [%.4f,%.4f] after Normalize, expected [-1,1]", lo, hi)
+	}
+	if lo, _, _, _ := noise.Statistics(); lo == -1 {
+		t.Errorf("Normalize changed the original grid")
+	}
+
+	img := norm.ToGrayImage()
+	if b := img.Bounds(); b.Dx() != 64 || b.Dy() != 48 {
+		t.Errorf("Got %dx%d image, expected 64x48", b.Dx(), b.Dy())
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGrid2DStatistics(t *testing.T) {
+	flat := NewGrid2D(9, 4)
+	for i := range flat.Data {
+		flat.Data[i] = -0.7
+	}
+	lo, hi, mean, sd := flat.Statistics()
+	if lo != -0.7 || hi != -0.7 || math.Abs(mean+0.7) > 1e-12 || sd > 1e-12 {
+		t.Errorf("Got (%.4f, %.4f, %.4f, %.4f), expected (-0.7, -0.7, -0.7, 0)", lo, hi, mean, sd)
+	}
+
+	// 0, 1, ..., n-1 has mean (n-1)/2 and variance (n^2-1)/12
+	ramp := NewGrid2D(10, 10)
+	for i := range ramp.Data {
+		ramp.Data[i] = float64(i)
+	}
+	lo, hi, mean, sd = ramp.Statistics()
+	if lo != 0 || hi != 99 {
+		t.Errorf("Got range [%.4f,%.4f], expected [0,99]", lo, hi)
+	}
+	if math.Abs(mean-49.5) > 1e-9 {
+		t.Errorf("Got mean %.4f, expected %.4f", mean, 49.5)
+	}
+	if expect := math.Sqrt((100*100 - 1) / 12.0); math.Abs(sd-expect) > 1e-9 {
+		t.Errorf("Got stddev %.4f, expected %.4f", sd, expect)
+	}
+
+	if lo, hi, mean, sd := NewGrid2D(0, 0).Statistics(); lo != 0 || hi != 0 || mean != 0 || sd != 0 {
+		t.Errorf("Got (%.4f, %.4f, %.4f, %.4f) for an empty grid, expected zeros", lo, hi, mean, sd)
+	}
+}
+
+func TestGrid2DAddScale(t *testing.T) {
+	n := New(rand.New(rand.NewSource(101)))
+
+	a := n.FillGrid2(nil, 16, 12, 0, 0, 0.1, 0.1)
+	b := n.FillGrid2(nil, 16, 12, 5, 5, 0.1, 0.1)
+	ab, err := a.Add(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ba, err := b.Add(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range ab.Data {
+		if ab.Data[i] != ba.Data[i] || ab.Data[i] != a.Data[i]+b.Data[i] {
+			t.Fatalf("Got %.4f and %.4f at %d, expected %.4f", ab.Data[i], ba.Data[i], i, a.Data[i]+b.Data[i])
+		}
+	}
+	if _, err := a.Add(NewGrid2D(12, 16)); err != ErrGridSize {
+		t.Errorf("Got error %v adding grids of different sizes, expected %v", err, ErrGridSize)
+	}
+
+	same := a.Scale(1)
+	neg := a.Scale(-1)
+	for i, v := range a.Data {
+		if same.Data[i] != v {
+			t.Fatalf("Got %.4f at %d after Scale(1), expected %.4f", same.Data[i], i, v)
+		}
+		if neg.Data[i] != -v {
+			t.Fatalf("Got %.4f at %d after Scale(-1), expected %.4f", neg.Data[i], i, -v)
+		}
+	}
+}
+
+func TestGrid2DBlend(t *testing.T) {
+	n := New(rand.New(rand.NewSource(101)))
+
+	a := n.FillGrid2(nil, 16, 12, 0, 0, 0.1, 0.1)
+	b := n.FillGrid2(nil, 16, 12, 5, 5, 0.1, 0.1)
+	mask := NewGrid2D(16, 12)
+
+	if _, err := a.Blend(NewGrid2D(12, 16), mask); err != ErrGridSize {
+		t.Errorf("Got error %v blending grids of different sizes, expected %v", err, ErrGridSize)
+	}
+	if _, err := a.Blend(b, NewGrid2D(16, 11)); err != ErrGridSize {
+		t.Errorf("Got error %v with a mask of a different size, expected %v", err, ErrGridSize)
+	}
+
+	for _, c := range []struct {
+		m      float64
+		expect func(i int) float64
+	}{
+		{0, func(i int) float64 { return a.Data[i] }},
+		{-3, func(i int) float64 { return a.Data[i] }},
+		{1, func(i int) float64 { return b.Data[i] }},
+		{7, func(i int) float64 { return b.Data[i] }},
+		{0.5, func(i int) float64 { return (a.Data[i] + b.Data[i]) / 2 }},
+	} {
+		for i := range mask.Data {
+			mask.Data[i] = c.m
+		}
+		out, err := a.Blend(b, mask)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, v := range out.Data {
+			if math.Abs(v-c.expect(i)) > 1e-12 {
+				t.Fatalf("mask %g: got %.4f at %d, expected %.4f", c.m, v, i, c.expect(i))
+			}
+		}
+	}
+}
+
+func TestGrid2DSmooth(t *testing.T) {
+	n := New(rand.New(rand.NewSource(101)))
+
+	flat := NewGrid2D(20, 10)
+	for i := range flat.Data {
+		flat.Data[i] = 0.3
+	}
+	for i, v := range flat.Smooth(3).Data {
+		if math.Abs(v-0.3) > 1e-12 {
+			t.Fatalf("Got %.4f at %d in a smoothed constant grid, expected %.4f", v, i, 0.3)
+		}
+	}
+
+	noise := n.FillGrid2(nil, 64, 48, 0, 0, 0.3, 0.3)
+	same := noise.Smooth(0)
+	if &same.Data[0] == &noise.Data[0] {
+		t.Errorf("Smooth(0) did not make a copy")
+	}
+	for i := range noise.Data {
+		if same.Data[i] != noise.Data[i] {
+			t.Fatalf("Got %.4f at %d after Smooth(0), expected %.4f", same.Data[i], i, noise.Data[i])
+		}
+	}
+
+	smooth := noise.Smooth(4)
+	if smooth.Width != 64 || smooth.Height != 48 || len(smooth.Data) != 64*48 {
+		t.Fatalf("Got %dx%d grid, expected 64x48", smooth.Width, smooth.Height)
+	}
+	roughness := func(g *Grid2D) float64 {
+		var sum float64
+		for y := 0; y < g.Height-1; y++ {
+			for x := 0; x < g.Width-1; x++ {
+				sum += math.Hypot(g.At(x+1, y)-g.At(x, y), g.At(x, y+1)-g.At(x, y))
+			}
+		}
+		return sum
+	}
+	if a, b := roughness(smooth), roughness(noise); a >= b/2 {
+		t.Errorf("Got total gradient %.4f after smoothing, expected well under %.4f", a, b)
+	}
+}
+
+// run with -race to check for data races
+func TestConcurrentFillGrid2(t *testing.T) {
+	n := New(rand.New(rand.NewSource(101)))
+
+	expect := n.FillGrid2(nil, 97, 61, 0.5, -2, 0.1, 0.15)
+	for _, workers := range []int{0, 1, 3, 8, 100} {
+		out := newGrid(97, 61)
+		n.ConcurrentFillGrid2(out, 0.5, -2, 0.1, 0.15, workers)
+		for row := range out {
+			for col, v := range out[row] {
+				if v != expect.At(col, row) {
+					t.Fatalf("%d workers: got %.4f at (%d,%d), expected %.4f", workers, v, col, row, expect.At(col, row))
+				}
+			}
+		}
+
+		g := n.ConcurrentFillGrid2D(nil, 97, 61, 0.5, -2, 0.1, 0.15, workers)
+		if g.Width != 97 || g.Height != 61 {
+			t.Fatalf("got %dx%d grid, expected 97x61", g.Width, g.Height)
+		}
+		g2 := NewGrid2D(97, 61)
+		if g3 := n.ConcurrentFillGrid2D(g2, 97, 61, 0.5, -2, 0.1, 0.15, workers); g3 != g2 {
+			t.Fatalf("ConcurrentFillGrid2D did not fill the grid it was given")
+		}
+		for i := range expect.Data {
+			if g.Data[i] != expect.Data[i] || g2.Data[i] != expect.Data[i] {
+				t.Fatalf("%d workers: got %.4f and %.4f at %d, expected %.4f", workers, g.Data[i], g2.Data[i], i, expect.Data[i])
+			}
+		}
+	}
+}
+
+func BenchmarkConcurrentFillGrid2(b *testing.B) {
+	n := New(rand.New(rand.NewSource(101)))
+	out := newGrid(2048, 2048)
+
+	for _, workers := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				n.ConcurrentFillGrid2(out, 0, 0, 0.01, 0.01, workers)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package simplex
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+// HeightMap is a grid of terrain heights, stored row-major
+type HeightMap struct {
+	Width, Height int
+	Data          []float64
+}
+
+// HeightMapConfig controls how GenerateHeightMap samples the noise.
+// Pixel (x, y) takes its height from FBM2 at (OffsetX + x*ScaleX,
+// OffsetY + y*ScaleY).
+type HeightMapConfig struct {
+	Octaves                 int
+	Lacunarity, Persistence float64
+	OffsetX, OffsetY        float64
+	ScaleX, ScaleY          float64
+}
+
+// GenerateHeightMap builds a width x height map of FBM2 noise
+func (s *Simplex) GenerateHeightMap(width, height int, cfg HeightMapConfig) *HeightMap {
+	if width < 0 {
+		width = 0
+	}
+	if height < 0 {
+		height = 0
+	}
+	h := &HeightMap{
+		Width:  width,
+		Height: height,
+		Data:   make([]float64, width*height),
+	}
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			h.Data[y*width+x] = s.FBM2(
+				cfg.OffsetX+float64(x)*cfg.ScaleX,
+				cfg.OffsetY+float64(y)*cfg.ScaleY,
+				cfg.Octaves, cfg.Lacunarity, cfg.Persistence)
+		}
+	}
+	return h
+}
+
+// At returns the height at (x, y)
+func (h *HeightMap) At(x, y int) float64 {
+	return h.Data[y*h.Width+x]
+}
+
+// Set changes the height at (x, y)
+func (h *HeightMap) Set(x, y int, v float64) {
+	h.Data[y*h.Width+x] = v
+}
+
+// Normalize linearly rescales the heights (in place) so they span
+// exactly [-1,1].  A flat map is set to all zeros.
+func (h *HeightMap) Normalize() {
+	lo := math.Inf(1)
+	hi := math.Inf(-1)
+	for _, v := range h.Data {
+		lo = math.Min(lo, v)
+		hi = math.Max(hi, v)
+	}
+	for i, v := range h.Data {
+		if hi > lo {
+			h.Data[i] = 2*(v-lo)/(hi-lo) - 1
+		} else {
+			h.Data[i] = 0
+		}
+	}
+}
+
+// ToGrayImage renders the map as an image, with heights in [-1,1]
+// mapped to black through white.  Heights out of range are clamped.
+func (h *HeightMap) ToGrayImage() *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, h.Width, h.Height))
+	for y := 0; y < h.Height; y++ {
+		for x := 0; x < h.Width; x++ {
+			img.SetGray(x, y, color.Gray{Y: grayLevel(h.At(x, y))})
+		}
+	}
+	return img
+}
+
+// grayLevel maps a value in [-1,1] to a gray level in [0,255]
+func grayLevel(v float64) uint8 {
+	g := math.Round((v + 1) / 2 * 255)
+	if g < 0 {
+		return 0
+	}
+	if g > 255 {
+		return 255
+	}
+	return uint8(g)
+}
+
+// ErosionGradients returns the height gradient (dh/dx, dh/dy) at each
+// cell, in the same row-major order as Data, for seeding hydraulic
+// erosion.  Water flows in the opposite direction, which is the
+// direction of steepest descent.  Interior cells use central
+// differences; edge cells fall back to one-sided differences.
+func (h *HeightMap) ErosionGradients() [][2]float64 {
+	grad := make([][2]float64, h.Width*h.Height)
+	for y := 0; y < h.Height; y++ {
+		y0, y1 := neighbors(y, h.Height)
+		for x := 0; x < h.Width; x++ {
+			x0, x1 := neighbors(x, h.Width)
+			var g [2]float64
+			if x1 > x0 {
+				g[0] = (h.At(x1, y) - h.At(x0, y)) / float64(x1-x0)
+			}
+			if y1 > y0 {
+				g[1] = (h.At(x, y1) - h.At(x, y0)) / float64(y1-y0)
+			}
+			grad[y*h.Width+x] = g
+		}
+	}
+	return grad
+}
+
+// neighbors returns the indices on either side of i, staying within
+// [0,n)
+func neighbors(i, n int) (int, int) {
+	lo, hi := i-1, i+1
+	if lo < 0 {
+		lo = 0
+	}
+	if hi >= n {
+		hi = n - 1
+	}
+	return lo, hi
+}
+
+// ToNormalMap renders the map as a tangent-space normal map.  The
+// surface normal at each cell is the cross product of the tangents
+// (1, 0, strength*dh/dx) and (0, 1, strength*dh/dy), normalized, and
+// each component v is packed into a channel as v*127+128.  Since the
+// normals point out of the surface, the blue channel is always above
+// 128 (unless the slope is absurdly steep).
+func (h *HeightMap) ToNormalMap(strength float64) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, h.Width, h.Height))
+	pack := func(v float64) uint8 {
+		return uint8(math.Round(v*127 + 128))
+	}
+	for i, g := range h.ErosionGradients() {
+		nx := -strength * g[0]
+		ny := -strength * g[1]
+		l := math.Sqrt(nx*nx + ny*ny + 1)
+		img.SetNRGBA(i%h.Width, i/h.Width, color.NRGBA{
+			R: pack(nx / l),
+			G: pack(ny / l),
+			B: pack(1 / l),
+			A: 255,
+		})
+	}
+	return img
+}
@@ -0,0 +1,163 @@
+package simplex
+
+import (
+	"bytes"
+	"image/png"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestHeightMap(t *testing.T) {
+	n := New(rand.New(rand.NewSource(101)))
+
+	cfg := HeightMapConfig{
+		Octaves:     4,
+		Lacunarity:  2,
+		Persistence: 0.5,
+		OffsetX:     10,
+		OffsetY:     20,
+		ScaleX:      0.05,
+		ScaleY:      0.05,
+	}
+	h := n.GenerateHeightMap(64, 32, cfg)
+	if h.Width != 64 || h.Height != 32 || len(h.Data) != 64*32 {
+		t.Fatalf("got %dx%d map with %d values, expected 64x32", h.Width, h.Height, len(h.Data))
+	}
+
+	a := h.At(5, 7)
+	a0 := n.FBM2(10+5*0.05, 20+7*0.05, 4, 2, 0.5)
+	if a != a0 {
+		t.Errorf("Got %.4f, expected %.4f", a, a0)
+	}
+
+	for _, size := range [][2]int{{0, 0}, {-5, 3}, {3, -5}, {-2, -3}} {
+		e := n.GenerateHeightMap(size[0], size[1], cfg)
+		if len(e.Data) != 0 || e.Width < 0 || e.Height < 0 {
+			t.Errorf("got %dx%d map with %d values for %v, expected an empty map", e.Width, e.Height, len(e.Data), size)
+		}
+	}
+
+	h.Set(63, 31, 0.125)
+	if a := h.At(63, 31); a != 0.125 {
+		t.Errorf("Got %.4f, expected %.4f", a, 0.125)
+	}
+
+	h.Normalize()
+	lo, hi := h.Data[0], h.Data[0]
+	for _, v := range h.Data {
+		if v < lo {
+			lo = v
+		}
+		if v > hi {
+			hi = v
+		}
+	}
+	if lo != -1 || hi != 1 {
+		t.Errorf("got range [%.4f, %.4f] after Normalize, expected [-1, 1]", lo, hi)
+	}
+
+	img := h.ToGrayImage()
+	if b := img.Bounds(); b.Dx() != 64 || b.Dy() != 32 {
+		t.Errorf("got %dx%d image, expected 64x32", b.Dx(), b.Dy())
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("could not encode image: %s", err)
+	}
+	back, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatalf("could not decode image: %s", err)
+	}
+	if back.Bounds() != img.Bounds() {
+		t.Errorf("got bounds %v after decoding, expected %v", back.Bounds(), img.Bounds())
+	}
+}
+
+func TestErosionGradients(t *testing.T) {
+	// a dome with its peak at (10, 8)
+	h := &HeightMap{Width: 21, Height: 17, Data: make([]float64, 21*17)}
+	for y := 0; y < h.Height; y++ {
+		for x := 0; x < h.Width; x++ {
+			dx := float64(x - 10)
+			dy := float64(y - 8)
+			h.Set(x, y, -(dx*dx+dy*dy)/100)
+		}
+	}
+	grad := h.ErosionGradients()
+	if len(grad) != h.Width*h.Height {
+		t.Fatalf("got %d gradients, expected %d", len(grad), h.Width*h.Height)
+	}
+	if g := grad[8*21+10]; math.Abs(g[0]) > 1e-12 || math.Abs(g[1]) > 1e-12 {
+		t.Errorf("got gradient %v at the peak, expected zero", g)
+	}
+	// on the slopes the gradient points back up toward the peak
+	if g := grad[8*21+15]; !(g[0] < 0) || math.Abs(g[1]) > 1e-12 {
+		t.Errorf("got gradient %v east of the peak, expected it to point west", g)
+	}
+	if g := grad[2*21+10]; !(g[1] > 0) || math.Abs(g[0]) > 1e-12 {
+		t.Errorf("got gradient %v north of the peak, expected it to point south", g)
+	}
+
+	// local maxima of a noise map are flat compared to its slopes
+	n := New(rand.New(rand.NewSource(101)))
+	hm := n.GenerateHeightMap(128, 128, HeightMapConfig{
+		Octaves:     1,
+		Lacunarity:  2,
+		Persistence: 0.5,
+		ScaleX:      0.02,
+		ScaleY:      0.02,
+	})
+	grad = hm.ErosionGradients()
+	var peaks, peak, slope float64
+	for y := 1; y < 127; y++ {
+		for x := 1; x < 127; x++ {
+			g := grad[y*128+x]
+			m := math.Hypot(g[0], g[1])
+			slope = math.Max(slope, m)
+			v := hm.At(x, y)
+			if v > hm.At(x-1, y) && v > hm.At(x+1, y) && v > hm.At(x, y-1) && v > hm.At(x, y+1) {
+				peaks++
+				peak = math.Max(peak, m)
+			}
+		}
+	}
+	if peaks == 0 {
+		t.Fatalf("found no local maxima")
+	}
+	if peak > slope/4 {
+		t.Errorf("got gradient %.4f at a local maximum, expected much less than the steepest %.4f", peak, slope)
+	}
+}
+
+func TestToNormalMap(t *testing.T) {
+	n := New(rand.New(rand.NewSource(101)))
+	hm := n.GenerateHeightMap(64, 64, HeightMapConfig{
+		Octaves:     6,
+		Lacunarity:  2,
+		Persistence: 0.5,
+		ScaleX:      0.05,
+		ScaleY:      0.05,
+	})
+
+	for _, strength := range []float64{0, 1, 10, 50} {
+		img := hm.ToNormalMap(strength)
+		if b := img.Bounds(); b.Dx() != 64 || b.Dy() != 64 {
+			t.Fatalf("got %dx%d image, expected 64x64", b.Dx(), b.Dy())
+		}
+		for y := 0; y < 64; y++ {
+			for x := 0; x < 64; x++ {
+				c := img.NRGBAAt(x, y)
+				if c.B <= 128 {
+					t.Fatalf("strength %g: got blue %d at (%d,%d), expected > 128", strength, c.B, x, y)
+				}
+			}
+		}
+	}
+
+	// a flat map points straight up
+	c := hm.ToNormalMap(0).NRGBAAt(10, 10)
+	if c.R != 128 || c.G != 128 || c.B != 255 {
+		t.Errorf("got %v for a flat map, expected {128 128 255 255}", c)
+	}
+}
@@ -0,0 +1,59 @@
+package simplex
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+)
+
+// NoiseImage is an image.Image whose pixels are computed on demand from
+// Noise2.  Pixel (x, y) samples the noise at (OffsetX+x*ScaleX,
+// OffsetY+y*ScaleY), and ColorFn maps the value to a color; if ColorFn
+// is nil, the image is grayscale with -1 as black and 1 as white.
+type NoiseImage struct {
+	S                *Simplex
+	Rect             image.Rectangle
+	OffsetX, OffsetY float64
+	ScaleX, ScaleY   float64
+	ColorFn          func(float64) color.Color
+}
+
+func (img *NoiseImage) ColorModel() color.Model {
+	if img.ColorFn == nil {
+		return color.GrayModel
+	}
+	return color.RGBAModel
+}
+
+func (img *NoiseImage) Bounds() image.Rectangle {
+	return img.Rect
+}
+
+func (img *NoiseImage) At(x, y int) color.Color {
+	v := img.S.Noise2(img.OffsetX+float64(x)*img.ScaleX, img.OffsetY+float64(y)*img.ScaleY)
+	if img.ColorFn == nil {
+		return color.Gray{grayLevel(v)}
+	}
+	return img.ColorFn(v)
+}
+
+// WritePNG writes a width x height grayscale PNG of s to w, with pixel
+// (x, y) showing Noise2(ox+x*sx, oy+y*sy) as NoiseImage would.  Any
+// error writing to w is returned.
+func WritePNG(w io.Writer, s *Simplex, width, height int, ox, oy, sx, sy float64) error {
+	return png.Encode(w, &NoiseImage{
+		S:       s,
+		Rect:    image.Rect(0, 0, width, height),
+		OffsetX: ox,
+		OffsetY: oy,
+		ScaleX:  sx,
+		ScaleY:  sy,
+	})
+}
+
+// NewGrayImage renders a width x height grayscale image of s, with
+// pixel (x, y) showing Noise2(ox+x*sx, oy+y*sy) as NoiseImage would
+func (s *Simplex) NewGrayImage(width, height int, ox, oy, sx, sy float64) *image.Gray {
+	return s.FillGrid2(nil, width, height, ox, oy, sx, sy).ToGrayImage()
+}
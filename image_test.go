@@ -0,0 +1,87 @@
+package simplex
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"math/rand"
+	"testing"
+)
+
+func TestNoiseImage(t *testing.T) {
+	n := New(rand.New(rand.NewSource(101)))
+
+	img := &NoiseImage{
+		S:      n,
+		Rect:   image.Rect(-10, 5, 54, 37),
+		ScaleX: 0.05,
+		ScaleY: 0.05,
+	}
+	if b := img.Bounds(); b != image.Rect(-10, 5, 54, 37) {
+		t.Errorf("Got bounds %v, expected %v", b, image.Rect(-10, 5, 54, 37))
+	}
+	if a, b := img.At(3, 7), img.At(3, 7); a != b {
+		t.Errorf("Got %v and then %v at the same pixel", a, b)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	back, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if back.Bounds().Size() != img.Bounds().Size() {
+		t.Errorf("Got decoded size %v, expected %v", back.Bounds().Size(), img.Bounds().Size())
+	}
+
+	img.ColorFn = func(v float64) color.Color {
+		if v > 0 {
+			return color.RGBA{0, 128, 0, 255}
+		}
+		return color.RGBA{0, 0, 255, 255}
+	}
+	buf.Reset()
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+}
+
+type failingWriter struct{}
+
+var errWrite = errors.New("write failed")
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errWrite
+}
+
+func TestWritePNG(t *testing.T) {
+	n := New(rand.New(rand.NewSource(101)))
+
+	var buf bytes.Buffer
+	if err := WritePNG(&buf, n, 40, 30, 1.5, -2, 0.05, 0.1); err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := img.Bounds(); b != image.Rect(0, 0, 40, 30) {
+		t.Fatalf("Got bounds %v, expected %v", b, image.Rect(0, 0, 40, 30))
+	}
+	for y := 0; y < 30; y++ {
+		for x := 0; x < 40; x++ {
+			expect := grayLevel(n.Noise2(1.5+float64(x)*0.05, -2+float64(y)*0.1))
+			if g := color.GrayModel.Convert(img.At(x, y)).(color.Gray); g.Y != expect {
+				t.Fatalf("Got %d at (%d,%d), expected %d", g.Y, x, y, expect)
+			}
+		}
+	}
+
+	if err := WritePNG(failingWriter{}, n, 40, 30, 0, 0, 0.05, 0.05); !errors.Is(err, errWrite) {
+		t.Errorf("Got error %v, expected %v", err, errWrite)
+	}
+}
@@ -0,0 +1,28 @@
+package simplex
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MapLegendMD returns a Markdown table describing a biome map for
+// documentation.  The biomes split the noise range [-1,1] into equal
+// bands, in order, and biome i is drawn in palette[i].  Biomes without
+// a palette entry are left out, but the bands of the others stay where
+// they are.
+func MapLegendMD(biomeNames []string, palette [][3]uint8) string {
+	n := len(biomeNames)
+
+	var b strings.Builder
+	b.WriteString("| Biome | Color | Noise range |\n")
+	b.WriteString("|-------|-------|-------------|\n")
+	for i := 0; i < n && i < len(palette); i++ {
+		c := palette[i]
+		code := fmt.Sprintf("#%02x%02x%02x", c[0], c[1], c[2])
+		lo := -1 + 2*float64(i)/float64(n)
+		hi := -1 + 2*float64(i+1)/float64(n)
+		fmt.Fprintf(&b, "| %s | <span style=\"color:%s\">&#9632;</span> `%s` | %.2f to %.2f |\n",
+			strings.ReplaceAll(biomeNames[i], "|", "\\|"), code, code, lo, hi)
+	}
+	return b.String()
+}
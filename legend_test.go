@@ -0,0 +1,46 @@
+package simplex
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMapLegendMD(t *testing.T) {
+	md := MapLegendMD(
+		[]string{"ocean", "beach", "forest", "snow"},
+		[][3]uint8{{0, 64, 255}, {240, 220, 130}, {34, 139, 34}, {255, 255, 255}})
+
+	lines := strings.Split(strings.TrimSpace(md), "\n")
+	if len(lines) != 6 {
+		t.Fatalf("got %d lines, expected 6:\n%s", len(lines), md)
+	}
+	expect := []string{
+		"| ocean | <span style=\"color:#0040ff\">&#9632;</span> `#0040ff` | -1.00 to -0.50 |",
+		"| beach | <span style=\"color:#f0dc82\">&#9632;</span> `#f0dc82` | -0.50 to 0.00 |",
+		"| forest | <span style=\"color:#228b22\">&#9632;</span> `#228b22` | 0.00 to 0.50 |",
+		"| snow | <span style=\"color:#ffffff\">&#9632;</span> `#ffffff` | 0.50 to 1.00 |",
+	}
+	for i, e := range expect {
+		if lines[i+2] != e {
+			t.Errorf("got line %q, expected %q", lines[i+2], e)
+		}
+	}
+
+	// biomes with no color are skipped, and the rest keep their bands
+	md = MapLegendMD([]string{"ocean", "beach"}, [][3]uint8{{0, 64, 255}})
+	if strings.Contains(md, "beach") {
+		t.Errorf("got a row for a biome with no color:\n%s", md)
+	}
+	md = MapLegendMD(
+		[]string{"ocean", "beach", "forest", "snow"},
+		[][3]uint8{{0, 64, 255}, {240, 220, 130}})
+	lines = strings.Split(strings.TrimSpace(md), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("got %d lines, expected 4:\n%s", len(lines), md)
+	}
+	for i, e := range expect[:2] {
+		if lines[i+2] != e {
+			t.Errorf("got line %q, expected %q", lines[i+2], e)
+		}
+	}
+}
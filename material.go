@@ -0,0 +1,20 @@
+package simplex
+
+// SubsurfaceDensity3 returns the scattering density of a translucent
+// material (skin, wax, marble) at (x,y,z).  The density varies with
+// FBM3 between 0 and 1/meanFreePath.  A meanFreePath that is not
+// positive gives a density of 0.
+func SubsurfaceDensity3(s *Simplex, x, y, z float64, meanFreePath float64) float64 {
+	if !(meanFreePath > 0) {
+		return 0
+	}
+	n := s.FBM3(x, y, z, 4, 2, 0.5)
+	return (n + 1) / 2 / meanFreePath
+}
+
+// SpecularRoughness2 returns a surface roughness for the GGX BRDF at
+// (x, y): baseRoughness plus up to variation either way, following
+// Noise2, clamped to [0,1].
+func SpecularRoughness2(s *Simplex, x, y float64, baseRoughness, variation float64) float64 {
+	return clamp01(baseRoughness + variation*s.Noise2(x, y))
+}
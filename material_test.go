@@ -0,0 +1,51 @@
+package simplex
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestSubsurfaceDensity3(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	mfp := 0.25
+	for i := 0; i < 100000; i++ {
+		x := r.Float64() * 10
+		y := r.Float64() * 10
+		z := r.Float64() * 10
+		d := SubsurfaceDensity3(n, x, y, z, mfp)
+		if d < 0 || d > 1/mfp {
+			t.Fatalf("got density %.4f, expected value in [0,%g]", d, 1/mfp)
+		}
+	}
+
+	for _, bad := range []float64{0, -0.25, math.NaN()} {
+		if d := SubsurfaceDensity3(n, 1.5, 2.5, 3.5, bad); d != 0 {
+			t.Errorf("got density %.4f for mean free path %g, expected 0", d, bad)
+		}
+	}
+}
+
+func TestSpecularRoughness2(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	var sum float64
+	for i := 0; i < 100000; i++ {
+		x := r.Float64() * 100
+		y := r.Float64() * 100
+		a := SpecularRoughness2(n, x, y, 0.4, 0.2)
+		if a < 0.2 || a > 0.6 {
+			t.Fatalf("got roughness %.4f, expected value in [0.2,0.6]", a)
+		}
+		sum += a
+		if a := SpecularRoughness2(n, x, y, 0.9, 0.5); a < 0 || a > 1 {
+			t.Fatalf("got roughness %.4f, expected value in [0,1]", a)
+		}
+	}
+	if mean := sum / 100000; mean < 0.38 || mean > 0.42 {
+		t.Errorf("got mean roughness %.4f, expected about 0.4", mean)
+	}
+}
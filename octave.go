@@ -0,0 +1,117 @@
+package simplex
+
+// OctaveNoise is a sum of Noise2 or Noise3 octaves, each with its own
+// frequency and amplitude, like FBM2 but with the octaves spelled out.
+// The sum is divided by the total amplitude so the result stays in
+// [-1,1].
+type OctaveNoise struct {
+	s          *Simplex
+	octaves    []octave
+	norm       float64
+	gain, bias float64
+}
+
+type octave struct {
+	frequency, amplitude float64
+}
+
+// NewOctaveNoise builds an OctaveNoise with the same octaves as
+// FBM2(x, y, octaves, lacunarity, persistence)
+func NewOctaveNoise(s *Simplex, octaves int, lacunarity, persistence float64) *OctaveNoise {
+	on := &OctaveNoise{s: s, gain: 0.5, bias: 0.5}
+	f := 1.0
+	a := 1.0
+	for i := 0; i < octaves; i++ {
+		on.octaves = append(on.octaves, octave{f, a})
+		f *= lacunarity
+		a *= persistence
+	}
+	on.normalize()
+	return on
+}
+
+// AddOctave adds another octave and returns the receiver, so calls can
+// be chained
+func (on *OctaveNoise) AddOctave(frequency, amplitude float64) *OctaveNoise {
+	on.octaves = append(on.octaves, octave{frequency, amplitude})
+	on.normalize()
+	return on
+}
+
+// WithOutputGain sets the Schlick gain applied to the sum (mapped to
+// [0,1] and back) and returns the receiver.  0.5 leaves it alone, higher
+// values push it away from 0 toward -1 and 1, and lower values pull it
+// in toward 0.
+func (on *OctaveNoise) WithOutputGain(g float64) *OctaveNoise {
+	on.gain = g
+	return on
+}
+
+// WithOutputBias sets the Schlick bias applied to the sum (mapped to
+// [0,1] and back) and returns the receiver.  0.5 leaves it alone, lower
+// values push it down toward -1, and higher values up toward 1.  The
+// bias is applied before the gain.
+func (on *OctaveNoise) WithOutputBias(b float64) *OctaveNoise {
+	on.bias = b
+	return on
+}
+
+// schlickBias is Schlick's fast approximation of Perlin's bias
+// function, t^(log b/log 0.5), for t in [0,1]
+func schlickBias(t, b float64) float64 {
+	return t / ((1/b-2)*(1-t) + 1)
+}
+
+// schlickGain is Perlin's gain function built on schlickBias: an S
+// curve through (0.5, 0.5) for g above 0.5, and its inverse below
+func schlickGain(t, g float64) float64 {
+	if t < 0.5 {
+		return schlickBias(2*t, 1-g) / 2
+	}
+	return 1 - schlickBias(2-2*t, 1-g)/2
+}
+
+// shape applies the output bias and gain to a sum in [-1,1]
+func (on *OctaveNoise) shape(v float64) float64 {
+	if on.bias == 0.5 && on.gain == 0.5 {
+		return v
+	}
+	t := (v + 1) / 2
+	t = schlickGain(schlickBias(t, on.bias), on.gain)
+	return 2*t - 1
+}
+
+func (on *OctaveNoise) normalize() {
+	var total float64
+	for _, o := range on.octaves {
+		total += o.amplitude
+	}
+	on.norm = 0
+	if total != 0 {
+		on.norm = 1 / total
+	}
+}
+
+func (on *OctaveNoise) Noise2(x, y float64) float64 {
+	var sum float64
+	for _, o := range on.octaves {
+		sum += o.amplitude * on.s.Noise2(x*o.frequency, y*o.frequency)
+	}
+	return on.shape(sum * on.norm)
+}
+
+func (on *OctaveNoise) Noise3(x, y, z float64) float64 {
+	var sum float64
+	for _, o := range on.octaves {
+		sum += o.amplitude * on.s.Noise3(x*o.frequency, y*o.frequency, z*o.frequency)
+	}
+	return on.shape(sum * on.norm)
+}
+
+func (on *OctaveNoise) Noise4(x, y, z, w float64) float64 {
+	var sum float64
+	for _, o := range on.octaves {
+		sum += o.amplitude * on.s.Noise4(x*o.frequency, y*o.frequency, z*o.frequency, w*o.frequency)
+	}
+	return on.shape(sum * on.norm)
+}
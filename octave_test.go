@@ -0,0 +1,94 @@
+package simplex
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestOctaveNoiseAddOctave(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	all := NewOctaveNoise(n, 5, 2, 0.5)
+	built := NewOctaveNoise(n, 0, 2, 0.5)
+	f, a := 1.0, 1.0
+	for i := 0; i < 5; i++ {
+		if on := built.AddOctave(f, a); on != built {
+			t.Fatalf("AddOctave did not return the receiver")
+		}
+		f *= 2
+		a *= 0.5
+	}
+
+	for i := 0; i < 1000; i++ {
+		x := r.Float64() * 10
+		y := r.Float64() * 10
+		z := r.Float64() * 10
+		if a, b := built.Noise2(x, y), all.Noise2(x, y); math.Abs(a-b) > 1e-12 {
+			t.Fatalf("Got %.4f, expected %.4f", a, b)
+		}
+		if a, b := built.Noise3(x, y, z), all.Noise3(x, y, z); math.Abs(a-b) > 1e-12 {
+			t.Fatalf("Got %.4f, expected %.4f", a, b)
+		}
+		if a, b := all.Noise2(x, y), n.FBM2(x, y, 5, 2, 0.5); math.Abs(a-b) > 1e-12 {
+			t.Fatalf("Got %.4f, expected the same as FBM2 %.4f", a, b)
+		}
+	}
+
+	if a := NewOctaveNoise(n, 0, 2, 0.5).Noise2(0.3, 0.4); a != 0 {
+		t.Errorf("Got %.4f with no octaves, expected 0", a)
+	}
+}
+
+func TestOctaveNoiseGainBias(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	plain := NewOctaveNoise(n, 5, 2, 0.5)
+	neutral := NewOctaveNoise(n, 5, 2, 0.5).WithOutputGain(0.5).WithOutputBias(0.5)
+	gained := NewOctaveNoise(n, 5, 2, 0.5).WithOutputGain(0.9)
+	biased := NewOctaveNoise(n, 5, 2, 0.5).WithOutputBias(0.1)
+
+	var mean, biasedMean, spread, gainedSpread float64
+	const samples = 10000
+	for i := 0; i < samples; i++ {
+		x := r.Float64() * 10
+		y := r.Float64() * 10
+		z := r.Float64() * 10
+		w := r.Float64() * 10
+		a := plain.Noise2(x, y)
+		if b := neutral.Noise2(x, y); a != b {
+			t.Fatalf("Got %.4f, expected %.4f", b, a)
+		}
+		if a, b := plain.Noise3(x, y, z), neutral.Noise3(x, y, z); a != b {
+			t.Fatalf("Got %.4f, expected %.4f", b, a)
+		}
+		if a, b := plain.Noise4(x, y, z, w), neutral.Noise4(x, y, z, w); a != b {
+			t.Fatalf("Got %.4f, expected %.4f", b, a)
+		}
+		for _, v := range []float64{gained.Noise2(x, y), biased.Noise3(x, y, z), gained.Noise4(x, y, z, w)} {
+			if v < -1 || v > 1 {
+				t.Fatalf("Got %.4f, expected value in [-1,1]", v)
+			}
+		}
+		mean += a
+		biasedMean += biased.Noise2(x, y)
+		spread += math.Abs(a)
+		gainedSpread += math.Abs(gained.Noise2(x, y))
+	}
+	if biasedMean > mean-0.3*samples {
+		t.Errorf("Got mean %.4f with bias 0.1, expected well below %.4f", biasedMean/samples, mean/samples)
+	}
+	if gainedSpread < 1.5*spread {
+		t.Errorf("Got mean |v| %.4f with gain 0.9, expected well above %.4f", gainedSpread/samples, spread/samples)
+	}
+
+	// the 0.5 settings are identities even when not skipped
+	for i := 0; i <= 10; i++ {
+		x := float64(i) / 10
+		if a := schlickGain(schlickBias(x, 0.5), 0.5); math.Abs(a-x) > 1e-12 {
+			t.Errorf("Got %.4f, expected %.4f", a, x)
+		}
+	}
+}
@@ -0,0 +1,317 @@
+package simplex
+
+import (
+	"math"
+)
+
+// OpenSimplex2 noise, after the public domain OpenSimplex2 (fast
+// variant) by KdotJPG.  It uses more gradient directions than the
+// classic simplex noise, and in 3D and 4D a different lattice (a pair of
+// interleaved cubic lattices in 3D, and five offset copies of the 4D
+// simplex lattice), which removes most of the axis-aligned artifacts.
+// Lattice points are hashed through the same permutation table as the
+// simplex noise.
+
+var os2Grad2 [24]grad2
+var os2Grad3 [48]grad3
+var os2Grad4 [32]grad4
+
+func init() {
+	// 24 unit vectors, 15 degrees apart and offset from the axes
+	for k := range os2Grad2 {
+		a := (float64(k)*15 + 7.5) * math.Pi / 180
+		os2Grad2[k] = grad2{math.Cos(a), math.Sin(a)}
+	}
+
+	// every signed permutation of (1+sqrt(3/2), 1+sqrt(3/2), 1) and of
+	// (b, c, 0), scaled to unit length
+	a := 1 + math.Sqrt(1.5)
+	n := math.Sqrt(2*a*a + 1)
+	b := 3.0862664687972017
+	c := math.Sqrt(2*a*a + 1 - b*b)
+	k := 0
+	for _, sx := range []float64{-1, 1} {
+		for _, sy := range []float64{-1, 1} {
+			for _, sz := range []float64{-1, 1} {
+				os2Grad3[k+0] = grad3{sx * a / n, sy * a / n, sz / n}
+				os2Grad3[k+1] = grad3{sx * a / n, sy / n, sz * a / n}
+				os2Grad3[k+2] = grad3{sx / n, sy * a / n, sz * a / n}
+				k += 3
+			}
+			os2Grad3[k+0] = grad3{sx * b / n, sy * c / n, 0}
+			os2Grad3[k+1] = grad3{sx * c / n, sy * b / n, 0}
+			os2Grad3[k+2] = grad3{sx * b / n, 0, sy * c / n}
+			os2Grad3[k+3] = grad3{sx * c / n, 0, sy * b / n}
+			os2Grad3[k+4] = grad3{0, sx * b / n, sy * c / n}
+			os2Grad3[k+5] = grad3{0, sx * c / n, sy * b / n}
+			k += 6
+		}
+	}
+
+	// the edges of the tesseract, scaled to unit length
+	for k, g := range g4 {
+		os2Grad4[k] = grad4{g.dx / math.Sqrt(3), g.dy / math.Sqrt(3), g.dz / math.Sqrt(3), g.dw / math.Sqrt(3)}
+	}
+}
+
+const (
+	os2Unskew2  = -0.21132486540518713
+	os2Scale2   = 1 / 0.01001634121365712
+	os2Scale3   = 3.3014957 / 0.07969837668935331
+	os2Skew4    = -0.138196601125011
+	os2Unskew4  = 0.309016994374947
+	os2Scale4   = 1 / 0.0220065933241897
+	os2Radius2  = 0.5
+	os2Radius3  = 0.6
+	os2Radius4  = 0.6
+	os2Lattice4 = 0.2
+)
+
+// OpenSimplex2_2D returns OpenSimplex2 noise at (x, y), in [-1,1]
+func (s *Simplex) OpenSimplex2_2D(x, y float64) float64 {
+	h := (x + y) * F2
+	xs, ys := x+h, y+h
+	i := fastfloor(xs)
+	j := fastfloor(ys)
+	xi := xs - float64(i)
+	yi := ys - float64(j)
+
+	contrib := func(i, j int, dx, dy float64) float64 {
+		a := os2Radius2 - dx*dx - dy*dy
+		if a <= 0 {
+			return 0
+		}
+		g := os2Grad2[s.getPerm(i+s.getPerm(j))%24]
+		return a * a * a * a * (g.dx*dx + g.dy*dy)
+	}
+
+	t := (xi + yi) * os2Unskew2
+	dx0 := xi + t
+	dy0 := yi + t
+	n := contrib(i, j, dx0, dy0)
+	n += contrib(i+1, j+1, dx0-(1+2*os2Unskew2), dy0-(1+2*os2Unskew2))
+	if dy0 > dx0 {
+		n += contrib(i, j+1, dx0-os2Unskew2, dy0-(os2Unskew2+1))
+	} else {
+		n += contrib(i+1, j, dx0-(os2Unskew2+1), dy0-os2Unskew2)
+	}
+	return n * os2Scale2
+}
+
+// OpenSimplex2_3D returns OpenSimplex2 noise at (x, y, z), in [-1,1]
+func (s *Simplex) OpenSimplex2_3D(x, y, z float64) float64 {
+	// reorient the lattice (this is orthonormal and keeps the main
+	// diagonal in place) so its faces don't line up with the axes
+	r := (x + y + z) * 2 / 3
+	x, y, z = r-x, r-y, r-z
+
+	i := int(math.Round(x))
+	j := int(math.Round(y))
+	k := int(math.Round(z))
+	x0 := x - float64(i)
+	y0 := y - float64(j)
+	z0 := z - float64(k)
+
+	// -1 when the offset is positive, 1 otherwise
+	sx, sy, sz := 1, 1, 1
+	if x0 > 0 {
+		sx = -1
+	}
+	if y0 > 0 {
+		sy = -1
+	}
+	if z0 > 0 {
+		sz = -1
+	}
+	ax := math.Abs(x0)
+	ay := math.Abs(y0)
+	az := math.Abs(z0)
+
+	contrib := func(i, j, k, m int, a, dx, dy, dz float64) float64 {
+		g := os2Grad3[s.getPerm(i+s.getPerm(j+s.getPerm(k+m*128)))%48]
+		return a * a * a * a * g.dot3(dx, dy, dz)
+	}
+
+	var n float64
+	a := os2Radius3 - x0*x0 - y0*y0 - z0*z0
+	for m := 0; ; m++ {
+		// the nearest point of this copy of the lattice, and the next
+		// nearest one along whichever axis is furthest off
+		if a > 0 {
+			n += contrib(i, j, k, m, a, x0, y0, z0)
+		}
+		if ax >= ay && ax >= az {
+			if b := a + 2*ax - 1; b > 0 {
+				n += contrib(i-sx, j, k, m, b, x0+float64(sx), y0, z0)
+			}
+		} else if ay > ax && ay >= az {
+			if b := a + 2*ay - 1; b > 0 {
+				n += contrib(i, j-sy, k, m, b, x0, y0+float64(sy), z0)
+			}
+		} else {
+			if b := a + 2*az - 1; b > 0 {
+				n += contrib(i, j, k-sz, m, b, x0, y0, z0+float64(sz))
+			}
+		}
+		if m == 1 {
+			break
+		}
+
+		// move on to the second lattice, offset by half a cell; its
+		// point at i-0.5 goes by the index i
+		ax, ay, az = 0.5-ax, 0.5-ay, 0.5-az
+		x0 = float64(sx) * ax
+		y0 = float64(sy) * ay
+		z0 = float64(sz) * az
+		a += (0.75 - ax) - (ay + az)
+		if sx < 0 {
+			i++
+		}
+		if sy < 0 {
+			j++
+		}
+		if sz < 0 {
+			k++
+		}
+		sx, sy, sz = -sx, -sy, -sz
+	}
+	return n * os2Scale3
+}
+
+// OpenSimplex2_4D returns OpenSimplex2 noise at (x, y, z, w), in [-1,1]
+func (s *Simplex) OpenSimplex2_4D(x, y, z, w float64) float64 {
+	h := (x + y + z + w) * os2Skew4
+	xs, ys, zs, ws := x+h, y+h, z+h, w+h
+	i := fastfloor(xs)
+	j := fastfloor(ys)
+	k := fastfloor(zs)
+	l := fastfloor(ws)
+	xi := xs - float64(i)
+	yi := ys - float64(j)
+	zi := zs - float64(k)
+	wi := ws - float64(l)
+
+	// start from the lattice copy most likely to hold a contributing
+	// vertex in the base simplex of its cell
+	sum := xi + yi + zi + wi
+	start := int(sum * 1.25)
+	offset := float64(start) * -os2Lattice4
+	xi += offset
+	yi += offset
+	zi += offset
+	wi += offset
+	ssi := (sum + offset*4) * os2Unskew4
+
+	var n float64
+	lattice := start
+	for m := 0; ; m++ {
+		// step to the closest vertex of the simplex based at this one
+		score0 := 1 - ssi/os2Unskew4
+		if xi >= yi && xi >= zi && xi >= wi && xi >= score0 {
+			i++
+			xi--
+			ssi -= os2Unskew4
+		} else if yi > xi && yi >= zi && yi >= wi && yi >= score0 {
+			j++
+			yi--
+			ssi -= os2Unskew4
+		} else if zi > xi && zi > yi && zi >= wi && zi >= score0 {
+			k++
+			zi--
+			ssi -= os2Unskew4
+		} else if wi > xi && wi > yi && wi > zi && wi >= score0 {
+			l++
+			wi--
+			ssi -= os2Unskew4
+		}
+
+		dx, dy, dz, dw := xi+ssi, yi+ssi, zi+ssi, wi+ssi
+		if a := dx*dx + dy*dy + dz*dz + dw*dw; a < os2Radius4 {
+			a = (a - os2Radius4) * (a - os2Radius4)
+			gi := s.getPerm(i+s.getPerm(j+s.getPerm(k+s.getPerm(l+lattice*51)))) & 31
+			n += a * a * os2Grad4[gi].dot(dx, dy, dz, dw)
+		}
+		if m == 4 {
+			break
+		}
+
+		// on to the next copy of the lattice, shifted by -0.2 along
+		// every skewed axis
+		xi += os2Lattice4
+		yi += os2Lattice4
+		zi += os2Lattice4
+		wi += os2Lattice4
+		ssi += os2Lattice4 * 4 * os2Unskew4
+		lattice--
+		if m == start {
+			i--
+			j--
+			k--
+			l--
+			lattice += 5
+		}
+	}
+	return n * os2Scale4
+}
+
+const (
+	os2sScale2  = 1 / 0.05481866495625118
+	os2sRadius2 = 2.0 / 3
+)
+
+// OpenSimplex2S_2D returns the smooth variant of OpenSimplex2 noise
+// (OpenSimplex2S, or "SuperSimplex") at (x, y), in [-1,1].  It sums a
+// larger kernel over the four nearest lattice points instead of three,
+// which makes the field smoother at the cost of some speed.
+func (s *Simplex) OpenSimplex2S_2D(x, y float64) float64 {
+	h := (x + y) * F2
+	xs, ys := x+h, y+h
+	i := fastfloor(xs)
+	j := fastfloor(ys)
+	xi := xs - float64(i)
+	yi := ys - float64(j)
+
+	t := (xi + yi) * os2Unskew2
+	dx0 := xi + t
+	dy0 := yi + t
+
+	// contrib is the contribution of the lattice point (i+di, j+dj)
+	contrib := func(di, dj int) float64 {
+		u := float64(di+dj) * os2Unskew2
+		dx := dx0 - float64(di) - u
+		dy := dy0 - float64(dj) - u
+		a := os2sRadius2 - dx*dx - dy*dy
+		if a <= 0 {
+			return 0
+		}
+		g := os2Grad2[s.getPerm(i+di+s.getPerm(j+dj))%24]
+		return a * a * a * a * (g.dx*dx + g.dy*dy)
+	}
+
+	n := contrib(0, 0) + contrib(1, 1)
+	xmyi := xi - yi
+	if t < os2Unskew2 {
+		if xi+xmyi > 1 {
+			n += contrib(2, 1)
+		} else {
+			n += contrib(0, 1)
+		}
+		if yi-xmyi > 1 {
+			n += contrib(1, 2)
+		} else {
+			n += contrib(1, 0)
+		}
+	} else {
+		if xi+xmyi < 0 {
+			n += contrib(-1, 0)
+		} else {
+			n += contrib(1, 0)
+		}
+		if yi < xmyi {
+			n += contrib(0, -1)
+		} else {
+			n += contrib(0, 1)
+		}
+	}
+	return n * os2sScale2
+}
@@ -0,0 +1,201 @@
+package simplex
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestOpenSimplex2Range(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	for i := 0; i < 100000; i++ {
+		x := r.Float64() * 100
+		y := r.Float64() * 100
+		z := r.Float64() * 100
+		w := r.Float64() * 100
+		for _, a := range []float64{
+			n.OpenSimplex2_2D(x, y),
+			n.OpenSimplex2_3D(x, y, z),
+			n.OpenSimplex2_4D(x, y, z, w),
+		} {
+			if a < -1 || a > 1 {
+				t.Fatalf("Got %.4f at (%g,%g,%g,%g), expected value in [-1,1]", a, x, y, z, w)
+			}
+		}
+	}
+}
+
+func TestOpenSimplex2Continuity(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	const e = 1e-7
+	for i := 0; i < 100000; i++ {
+		x := r.Float64() * 100
+		y := r.Float64() * 100
+		z := r.Float64() * 100
+		w := r.Float64() * 100
+		for _, d := range []float64{
+			n.OpenSimplex2_2D(x, y) - n.OpenSimplex2_2D(x+e, y-e),
+			n.OpenSimplex2_3D(x, y, z) - n.OpenSimplex2_3D(x+e, y-e, z+e),
+			n.OpenSimplex2_4D(x, y, z, w) - n.OpenSimplex2_4D(x+e, y-e, z+e, w-e),
+		} {
+			if math.Abs(d) > 20*e {
+				t.Fatalf("Got a jump of %g near (%g,%g,%g,%g)", d, x, y, z, w)
+			}
+		}
+	}
+}
+
+// gradientSpread samples the gradient of f around a ring centered on the
+// origin and returns the relative standard deviation of the histogram of
+// gradient directions, which is 0 for perfectly isotropic noise
+func gradientSpread(f func(x, y float64) float64) float64 {
+	const bins = 36
+	const e = 1e-4
+	var hist [bins]float64
+	for a := 0; a < 7200; a++ {
+		th := float64(a) * 2 * math.Pi / 7200
+		for radius := 50.0; radius < 60; radius += 0.5 {
+			x, y := radius*math.Cos(th), radius*math.Sin(th)
+			gx := f(x+e, y) - f(x-e, y)
+			gy := f(x, y+e) - f(x, y-e)
+			ang := math.Atan2(gy, gx)
+			if ang < 0 {
+				ang += math.Pi
+			}
+			hist[int(ang/math.Pi*bins)%bins]++
+		}
+	}
+	var mean, v float64
+	for _, h := range hist {
+		mean += h
+	}
+	mean /= bins
+	for _, h := range hist {
+		v += (h - mean) * (h - mean)
+	}
+	return math.Sqrt(v/bins) / mean
+}
+
+func TestOpenSimplex2Isotropy(t *testing.T) {
+	n := New(rand.New(rand.NewSource(101)))
+
+	simplex := gradientSpread(n.Noise2)
+	open := gradientSpread(n.OpenSimplex2_2D)
+	if open > simplex*0.7 {
+		t.Errorf("Got spread %.4f for OpenSimplex2, expected well under %.4f for Noise2", open, simplex)
+	}
+}
+
+func TestOpenSimplex2SRange(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	for i := 0; i < 100000; i++ {
+		x := r.Float64() * 100
+		y := r.Float64() * 100
+		if a := n.OpenSimplex2S_2D(x, y); a < -1 || a > 1 {
+			t.Fatalf("Got %.4f at (%g,%g), expected value in [-1,1]", a, x, y)
+		}
+	}
+}
+
+// boundaryJump returns the largest change in the (finite difference)
+// gradient of f across the edges of the skewed simplex cells.  For a
+// field with a continuous gradient this shrinks with the distance d
+// between the two sides; a kink would show up as a jump of order 1.
+func boundaryJump(f func(x, y float64) float64) float64 {
+	r := rand.New(rand.NewSource(101))
+	const d = 1e-5
+	const e = 1e-6
+	grad := func(xs, ys float64) (float64, float64) {
+		u := (xs + ys) * os2Unskew2
+		x, y := xs+u, ys+u
+		return (f(x+e, y) - f(x-e, y)) / (2 * e), (f(x, y+e) - f(x, y-e)) / (2 * e)
+	}
+	var worst float64
+	for i := 0; i < 10000; i++ {
+		k := float64(r.Intn(100))
+		v := r.Float64() * 100
+		for _, p := range [][4]float64{
+			{k + d, v, k - d, v},         // across xs = k
+			{v, k + d, v, k - d},         // across ys = k
+			{v + d, v - d, v - d, v + d}, // across the diagonal
+		} {
+			gx1, gy1 := grad(p[0], p[1])
+			gx2, gy2 := grad(p[2], p[3])
+			worst = math.Max(worst, math.Hypot(gx1-gx2, gy1-gy2))
+		}
+	}
+	return worst
+}
+
+func TestOpenSimplex2SGradientContinuity(t *testing.T) {
+	n := New(rand.New(rand.NewSource(101)))
+
+	if j := boundaryJump(n.OpenSimplex2S_2D); j > 0.002 {
+		t.Errorf("Got gradient jump %.6f at a cell boundary, expected at most %.6f", j, 0.002)
+	}
+}
+
+func BenchmarkOpenSimplex2(b *testing.B) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	x := r.Float64()
+	y := r.Float64()
+	z := r.Float64()
+	w := r.Float64()
+	b.Run("2D", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			n.OpenSimplex2_2D(x, y)
+			x += 0.00000011
+			y += 0.00000012
+		}
+	})
+	b.Run("3D", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			n.OpenSimplex2_3D(x, y, z)
+			x += 0.00000011
+			y += 0.00000012
+			z += 0.00000013
+		}
+	})
+	b.Run("4D", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			n.OpenSimplex2_4D(x, y, z, w)
+			x += 0.00000011
+			y += 0.00000012
+			z += 0.00000013
+			w += 0.00000014
+		}
+	})
+}
+
+// compare the cost of the three 2D variants
+func BenchmarkNoise2Variants(b *testing.B) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	for _, v := range []struct {
+		name string
+		f    func(x, y float64) float64
+	}{
+		{"Simplex", n.Noise2},
+		{"OpenSimplex2", n.OpenSimplex2_2D},
+		{"OpenSimplex2S", n.OpenSimplex2S_2D},
+	} {
+		x := r.Float64()
+		y := r.Float64()
+		b.Run(v.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				v.f(x, y)
+				x += 0.00000011
+				y += 0.00000012
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package simplex
+
+import (
+	"math"
+)
+
+// Small named operations on Noise2 (and Noise3) values, for building up
+// noise pipelines where a bare expression would hide the intent.
+
+// Abs2 returns |Noise2(x, y)|, in [0,1], the building block of
+// Turbulence2 and billowy noise
+func (s *Simplex) Abs2(x, y float64) float64 {
+	return math.Abs(s.Noise2(x, y))
+}
+
+// Abs3 is the 3D version of Abs2
+func (s *Simplex) Abs3(x, y, z float64) float64 {
+	return math.Abs(s.Noise3(x, y, z))
+}
+
+// Invert2 returns -Noise2(x, y), which turns ridges into valleys
+func (s *Simplex) Invert2(x, y float64) float64 {
+	return -s.Noise2(x, y)
+}
+
+// Clamp2 returns Noise2(x, y) limited to [lo, hi]
+func (s *Simplex) Clamp2(x, y, lo, hi float64) float64 {
+	return math.Max(lo, math.Min(hi, s.Noise2(x, y)))
+}
+
+// Sign2 returns 1 where Noise2(x, y) is positive and -1 where it is
+// negative (following the sign bit where it is exactly zero)
+func (s *Simplex) Sign2(x, y float64) float64 {
+	return math.Copysign(1, s.Noise2(x, y))
+}
+
+// Step2 returns 1 where Noise2(x, y) is above threshold and 0
+// elsewhere
+func (s *Simplex) Step2(x, y, threshold float64) float64 {
+	if s.Noise2(x, y) > threshold {
+		return 1
+	}
+	return 0
+}
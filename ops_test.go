@@ -0,0 +1,123 @@
+package simplex
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestAbs(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	for i := 0; i < 100000; i++ {
+		x := r.Float64()*200 - 100
+		y := r.Float64()*200 - 100
+		z := r.Float64()*200 - 100
+		if a := n.Abs2(x, y); a < 0 || a > 1 || a != math.Abs(n.Noise2(x, y)) {
+			t.Fatalf("Got %.4f at (%g,%g), expected %.4f", a, x, y, math.Abs(n.Noise2(x, y)))
+		}
+		if a := n.Abs3(x, y, z); a < 0 || a > 1 || a != math.Abs(n.Noise3(x, y, z)) {
+			t.Fatalf("Got %.4f at (%g,%g,%g), expected %.4f", a, x, y, z, math.Abs(n.Noise3(x, y, z)))
+		}
+	}
+}
+
+func TestInvert2(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	for i := 0; i < 100000; i++ {
+		x := r.Float64()*200 - 100
+		y := r.Float64()*200 - 100
+		if a := n.Invert2(x, y) + n.Noise2(x, y); a != 0 {
+			t.Fatalf("Got %g at (%g,%g), expected 0", a, x, y)
+		}
+	}
+}
+
+func TestClamp2(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	var below, inside, above int
+	for i := 0; i < 100000; i++ {
+		x := r.Float64()*200 - 100
+		y := r.Float64()*200 - 100
+		v := n.Noise2(x, y)
+		a := n.Clamp2(x, y, -0.3, 0.4)
+		switch {
+		case v < -0.3:
+			below++
+			if a != -0.3 {
+				t.Fatalf("Got %.4f for %.4f, expected %.4f", a, v, -0.3)
+			}
+		case v > 0.4:
+			above++
+			if a != 0.4 {
+				t.Fatalf("Got %.4f for %.4f, expected %.4f", a, v, 0.4)
+			}
+		default:
+			inside++
+			if a != v {
+				t.Fatalf("Got %.4f, expected %.4f unchanged", a, v)
+			}
+		}
+	}
+	if below == 0 || inside == 0 || above == 0 {
+		t.Errorf("got %d below, %d inside and %d above, expected some of each", below, inside, above)
+	}
+}
+
+func TestSign2(t *testing.T) {
+	n := New(rand.New(rand.NewSource(101)))
+
+	const size = 200
+	g := n.FillGrid2(nil, size, size, -3, 7, 0.05, 0.05)
+	var crossings int
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			a := n.Sign2(-3+float64(x)*0.05, 7+float64(y)*0.05)
+			if a != 1 && a != -1 {
+				t.Fatalf("Got %.4f at (%d,%d), expected 1 or -1", a, x, y)
+			}
+			if x+1 == size {
+				continue
+			}
+			// the sign flips between neighbors exactly where the noise
+			// crosses zero (the grid hits some lattice points, where the
+			// noise is exactly zero, so go by the sign bit)
+			b := n.Sign2(-3+float64(x+1)*0.05, 7+float64(y)*0.05)
+			crosses := math.Signbit(g.At(x, y)) != math.Signbit(g.At(x+1, y))
+			if (a != b) != crosses {
+				t.Fatalf("Got signs %.0f and %.0f for %.4f and %.4f", a, b, g.At(x, y), g.At(x+1, y))
+			}
+			if crosses {
+				crossings++
+			}
+		}
+	}
+	if crossings == 0 {
+		t.Errorf("got no zero crossings, expected some")
+	}
+}
+
+func TestStep2(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	var ones float64
+	const samples = 1000000
+	for i := 0; i < samples; i++ {
+		x := r.Float64()*2000 - 1000
+		y := r.Float64()*2000 - 1000
+		a := n.Step2(x, y, 0)
+		if a != 0 && a != 1 {
+			t.Fatalf("Got %.4f at (%g,%g), expected 0 or 1", a, x, y)
+		}
+		ones += a
+	}
+	if f := ones / samples; math.Abs(f-0.5) > 0.01 {
+		t.Errorf("Got %.4f of points above 0, expected about %.4f", f, 0.5)
+	}
+}
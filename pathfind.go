@@ -0,0 +1,98 @@
+package simplex
+
+import (
+	"container/heap"
+	"math"
+)
+
+// SpeedrunPath2 finds the cheapest route from start to goal across a
+// w x h grid whose per-cell cost is derived from Noise2 at (x*step,
+// y*step): cells cost between 1 (noise at -1) and 5 (noise at 1) to
+// cross.  Moves go to any of the 8 neighbors, using Dijkstra's
+// algorithm.  The start and goal are rounded to the nearest cell; the
+// path is returned as the list of cells visited, including both ends,
+// or nil if either end is off the grid.
+func SpeedrunPath2(s *Simplex, start, goal [2]float64, w, h int, step float64) [][2]float64 {
+	sx, sy := int(math.Round(start[0])), int(math.Round(start[1]))
+	gx, gy := int(math.Round(goal[0])), int(math.Round(goal[1]))
+	if sx < 0 || sy < 0 || sx >= w || sy >= h || gx < 0 || gy < 0 || gx >= w || gy >= h {
+		return nil
+	}
+
+	cost := make([]float64, w*h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			cost[y*w+x] = 3 + 2*s.Noise2(float64(x)*step, float64(y)*step)
+		}
+	}
+
+	dist := make([]float64, w*h)
+	prev := make([]int, w*h)
+	for i := range dist {
+		dist[i] = math.Inf(1)
+		prev[i] = -1
+	}
+	src := sy*w + sx
+	dst := gy*w + gx
+	dist[src] = 0
+	q := &pathQueue{{src, 0}}
+	for q.Len() > 0 {
+		e := heap.Pop(q).(pathEntry)
+		if e.dist > dist[e.cell] {
+			continue
+		}
+		if e.cell == dst {
+			break
+		}
+		cx, cy := e.cell%w, e.cell/w
+		for dy := -1; dy <= 1; dy++ {
+			for dx := -1; dx <= 1; dx++ {
+				nx, ny := cx+dx, cy+dy
+				if (dx == 0 && dy == 0) || nx < 0 || ny < 0 || nx >= w || ny >= h {
+					continue
+				}
+				n := ny*w + nx
+				// pay half of each cell's cost for the part of
+				// the move spent in it
+				d := e.dist + (cost[e.cell]+cost[n])/2*math.Hypot(float64(dx), float64(dy))
+				if d < dist[n] {
+					dist[n] = d
+					prev[n] = e.cell
+					heap.Push(q, pathEntry{n, d})
+				}
+			}
+		}
+	}
+
+	var path [][2]float64
+	for c := dst; c != -1; c = prev[c] {
+		path = append(path, [2]float64{float64(c % w), float64(c / w)})
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+type pathEntry struct {
+	cell int
+	dist float64
+}
+
+// pathQueue is a min-heap of pathEntry ordered by distance
+type pathQueue []pathEntry
+
+func (q pathQueue) Len() int           { return len(q) }
+func (q pathQueue) Less(i, j int) bool { return q[i].dist < q[j].dist }
+func (q pathQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+
+func (q *pathQueue) Push(x interface{}) {
+	*q = append(*q, x.(pathEntry))
+}
+
+func (q *pathQueue) Pop() interface{} {
+	old := *q
+	e := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return e
+}
@@ -0,0 +1,38 @@
+package simplex
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestSpeedrunPath2(t *testing.T) {
+	n := New(rand.New(rand.NewSource(101)))
+
+	start := [2]float64{2, 3}
+	goal := [2]float64{45, 37}
+	path := SpeedrunPath2(n, start, goal, 50, 40, 0.1)
+	if len(path) == 0 {
+		t.Fatalf("got no path")
+	}
+	if path[0] != start || path[len(path)-1] != goal {
+		t.Errorf("got path from %v to %v, expected %v to %v", path[0], path[len(path)-1], start, goal)
+	}
+	for i := 1; i < len(path); i++ {
+		dx := math.Abs(path[i][0] - path[i-1][0])
+		dy := math.Abs(path[i][1] - path[i-1][1])
+		if dx > 1 || dy > 1 || (dx == 0 && dy == 0) {
+			t.Fatalf("path jumps from %v to %v", path[i-1], path[i])
+		}
+	}
+
+	// a flat cost map gives a shortest (8-connected) path
+	flat := SpeedrunPath2(n, start, goal, 50, 40, 0)
+	if len(flat) != 44 {
+		t.Errorf("got path of %d cells on flat ground, expected 44", len(flat))
+	}
+
+	if p := SpeedrunPath2(n, start, [2]float64{60, 0}, 50, 40, 0.1); p != nil {
+		t.Errorf("got a path to a goal off the map")
+	}
+}
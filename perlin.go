@@ -0,0 +1,56 @@
+package simplex
+
+// classic Perlin gradient noise, using the same permutation table (and
+// gradient set) as the simplex noise so the two can be compared
+// directly for the same seed
+
+// scurve is the 3t^2-2t^3 fade curve from Perlin's original noise
+func scurve(t float64) float64 {
+	return t * t * (3 - 2*t)
+}
+
+// PerlinNoise2 returns classic 2D Perlin noise at (x, y), in [-1,1]
+func (s *Simplex) PerlinNoise2(x, y float64) float64 {
+	i := fastfloor(x)
+	j := fastfloor(y)
+	x0 := x - float64(i)
+	y0 := y - float64(j)
+	u := scurve(x0)
+	v := scurve(y0)
+
+	n00 := g3[s.getPermMod12(i+s.getPerm(j))].dot(x0, y0)
+	n10 := g3[s.getPermMod12(i+1+s.getPerm(j))].dot(x0-1, y0)
+	n01 := g3[s.getPermMod12(i+s.getPerm(j+1))].dot(x0, y0-1)
+	n11 := g3[s.getPermMod12(i+1+s.getPerm(j+1))].dot(x0-1, y0-1)
+
+	return lerp(lerp(n00, n10, u), lerp(n01, n11, u), v)
+}
+
+// perlin3Peak is the largest |PerlinNoise3| before scaling: the
+// trilinear weights are never negative, so the peak is the largest
+// weighted sum of each corner's best gradient, 1.04490519 at about
+// (0.476, 0.5, 0.676) in a cell (rounded up here)
+const perlin3Peak = 1.0449052
+
+// PerlinNoise3 returns classic 3D Perlin noise at (x, y, z), in [-1,1]
+func (s *Simplex) PerlinNoise3(x, y, z float64) float64 {
+	i := fastfloor(x)
+	j := fastfloor(y)
+	k := fastfloor(z)
+	x0 := x - float64(i)
+	y0 := y - float64(j)
+	z0 := z - float64(k)
+	u := scurve(x0)
+	v := scurve(y0)
+	w := scurve(z0)
+
+	g := func(di, dj, dk int) float64 {
+		gi := s.getPermMod12(i + di + s.getPerm(j+dj+s.getPerm(k+dk)))
+		return g3[gi].dot3(x0-float64(di), y0-float64(dj), z0-float64(dk))
+	}
+
+	return lerp(
+		lerp(lerp(g(0, 0, 0), g(1, 0, 0), u), lerp(g(0, 1, 0), g(1, 1, 0), u), v),
+		lerp(lerp(g(0, 0, 1), g(1, 0, 1), u), lerp(g(0, 1, 1), g(1, 1, 1), u), v),
+		w) / perlin3Peak
+}
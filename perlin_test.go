@@ -0,0 +1,79 @@
+package simplex
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestPerlinNoise2(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	for i := 0; i < 1000000; i++ {
+		x := r.Float64() * 100
+		y := r.Float64() * 100
+		a := n.PerlinNoise2(x, y)
+		if a < -1 || a > 1 {
+			t.Fatalf("got %.4f at (%g, %g), expected value in [-1,1]", a, x, y)
+		}
+	}
+
+	// Perlin noise lives on a coarser lattice than simplex noise,
+	// so its features are larger
+	lp := correlationLength(n.PerlinNoise2, 1, 0)
+	ls := correlationLength(n.Noise2, 1, 0)
+	if lp <= ls*1.2 {
+		t.Errorf("got correlation length %.3f for Perlin noise and %.3f for simplex noise, expected them to differ", lp, ls)
+	}
+}
+
+// compare with BenchmarkSimplex
+func BenchmarkPerlinNoise2(b *testing.B) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	x := 0.001
+	y := 0.0001
+	for i := 0; i < b.N; i++ {
+		n.PerlinNoise2(x, y)
+		x += 0.00000011
+		y += 0.00000012
+	}
+}
+
+func TestPerlinNoise3(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	for i := 0; i < 1000000; i++ {
+		x := r.Float64() * 100
+		y := r.Float64() * 100
+		z := r.Float64() * 100
+		a := n.PerlinNoise3(x, y, z)
+		if a < -1 || a > 1 {
+			t.Fatalf("got %.4f at (%g, %g, %g), expected value in [-1,1]", a, x, y, z)
+		}
+	}
+
+	// the unscaled noise peaks at 1.0449 here
+	if a := n.PerlinNoise3(120.5, 190.675, 200.475); a < -1 || a > 1 || a < 0.999 {
+		t.Errorf("got %.6f at the peak, expected just under 1", a)
+	}
+}
+
+// compare with BenchmarkNoise3; in theory simplex noise should be
+// 2-4x faster in 3D
+func BenchmarkPerlinNoise3(b *testing.B) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	x := 0.001
+	y := 0.0001
+	z := 0.00001
+	for i := 0; i < b.N; i++ {
+		n.PerlinNoise3(x, y, z)
+		x += 0.00000011
+		y += 0.00000012
+		z += 0.00000013
+	}
+}
@@ -0,0 +1,91 @@
+package simplex
+
+// NoiseQuadTree caches Noise2 over a square region at several levels
+// of detail.  Each node covers a square and holds the noise value at
+// its center, which is only computed the first time it is asked for.
+type NoiseQuadTree struct {
+	s        *Simplex
+	maxDepth int
+	root     quadNode
+}
+
+type quadNode struct {
+	x, y, size float64
+	value      float64
+	valid      bool
+	children   *[4]quadNode
+}
+
+// NewNoiseQuadTree makes an (empty) cache for the square with lower
+// corner (x, y) and the given size.  The tree is never subdivided more
+// than maxDepth times.
+func NewNoiseQuadTree(s *Simplex, x, y, size float64, maxDepth int) *NoiseQuadTree {
+	return &NoiseQuadTree{
+		s:        s,
+		maxDepth: maxDepth,
+		root:     quadNode{x: x, y: y, size: size},
+	}
+}
+
+// Query returns the noise near (x, y) at a resolution of desiredLOD,
+// which is the largest acceptable cell size.  Distant queries should
+// ask for a large desiredLOD and will be answered from the coarse
+// levels of the tree; nearby ones ask for a small desiredLOD.  Points
+// outside the tree are not cached and are evaluated exactly.
+func (q *NoiseQuadTree) Query(x, y, desiredLOD float64) float64 {
+	if !q.root.contains(x, y) {
+		return q.s.Noise2(x, y)
+	}
+	n := &q.root
+	for depth := 0; depth < q.maxDepth && n.size > desiredLOD; depth++ {
+		n = n.child(x, y)
+	}
+	if !n.valid {
+		n.value = q.s.Noise2(n.x+n.size/2, n.y+n.size/2)
+		n.valid = true
+	}
+	return n.value
+}
+
+// Invalidate drops every cached value whose cell overlaps the
+// rectangle from (x0, y0) to (x1, y1); they will be re-evaluated when
+// they are next queried.
+func (q *NoiseQuadTree) Invalidate(x0, y0, x1, y1 float64) {
+	q.root.invalidate(x0, y0, x1, y1)
+}
+
+func (n *quadNode) contains(x, y float64) bool {
+	return x >= n.x && x < n.x+n.size && y >= n.y && y < n.y+n.size
+}
+
+func (n *quadNode) child(x, y float64) *quadNode {
+	h := n.size / 2
+	if n.children == nil {
+		n.children = &[4]quadNode{
+			{x: n.x, y: n.y, size: h},
+			{x: n.x + h, y: n.y, size: h},
+			{x: n.x, y: n.y + h, size: h},
+			{x: n.x + h, y: n.y + h, size: h},
+		}
+	}
+	i := 0
+	if x >= n.x+h {
+		i |= 1
+	}
+	if y >= n.y+h {
+		i |= 2
+	}
+	return &n.children[i]
+}
+
+func (n *quadNode) invalidate(x0, y0, x1, y1 float64) {
+	if x1 < n.x || x0 >= n.x+n.size || y1 < n.y || y0 >= n.y+n.size {
+		return
+	}
+	n.valid = false
+	if n.children != nil {
+		for i := range n.children {
+			n.children[i].invalidate(x0, y0, x1, y1)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package simplex
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestNoiseQuadTree(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+	q := NewNoiseQuadTree(n, 0, 0, 16, 10)
+
+	// the coarsest level is the noise at the center of the region
+	a := q.Query(3, 4, 100)
+	a0 := n.Noise2(8, 8)
+	if a != a0 {
+		t.Errorf("Got %.4f, expected %.4f", a, a0)
+	}
+
+	// the finest level is the noise at the center of a tiny cell
+	cell := 16.0 / 1024
+	a = q.Query(3.001, 4.001, 0)
+	a0 = n.Noise2(3+cell/2, 4+cell/2)
+	if a != a0 {
+		t.Errorf("Got %.4f, expected %.4f", a, a0)
+	}
+
+	// points outside the tree are evaluated exactly
+	a = q.Query(-1.5, 20, 1)
+	a0 = n.Noise2(-1.5, 20)
+	if a != a0 {
+		t.Errorf("Got %.4f, expected %.4f", a, a0)
+	}
+
+	// cached values survive until they are invalidated, even if the
+	// underlying permutation changes
+	before := q.Query(5, 5, 1)
+	for i := range n.mix {
+		n.mix[i] = 255 - n.mix[i]
+	}
+	if q.Query(5, 5, 1) != before {
+		t.Errorf("cached value changed without invalidation")
+	}
+	q.Invalidate(4, 4, 6, 6)
+	fresh := n.Noise2(5.5, 5.5)
+	if got := q.Query(5, 5, 1); got != fresh {
+		t.Errorf("Got %.4f after invalidation, expected %.4f", got, fresh)
+	}
+}
@@ -0,0 +1,128 @@
+package simplex
+
+import (
+	"math"
+	"math/rand"
+)
+
+// ChiSquaredUniformityTest2 sorts the given number of random samples of
+// Noise2 into bins equal-width buckets over [-1,1] and returns the
+// chi-squared statistic against a uniform distribution, along with its
+// p-value.  Noise values bunch up around 0, so the raw output is never
+// close to uniform; this is for comparing changes to the shuffle or the
+// gradient tables against each other, not for a pass/fail verdict.
+func (s *Simplex) ChiSquaredUniformityTest2(samples, bins int, r *rand.Rand) (chiSq, pValue float64) {
+	counts := make([]int, bins)
+	for i := 0; i < samples; i++ {
+		a := s.Noise2(r.Float64()*256, r.Float64()*256)
+		b := int((a + 1) / 2 * float64(bins))
+		if b < 0 {
+			b = 0
+		}
+		if b >= bins {
+			b = bins - 1
+		}
+		counts[b]++
+	}
+	return chiSquaredUniform(counts)
+}
+
+// chiSquaredUniform returns the chi-squared statistic of the counts
+// against equal expected counts in every bin, and its p-value
+func chiSquaredUniform(counts []int) (chiSq, pValue float64) {
+	if len(counts) < 2 {
+		return 0, 1
+	}
+	var total int
+	for _, c := range counts {
+		total += c
+	}
+	if total == 0 {
+		return 0, 1
+	}
+	expect := float64(total) / float64(len(counts))
+	for _, c := range counts {
+		d := float64(c) - expect
+		chiSq += d * d / expect
+	}
+	return chiSq, gammaQ(float64(len(counts)-1)/2, chiSq/2)
+}
+
+// gammaQ is the upper regularized incomplete gamma function Q(a, x),
+// by the series for x < a+1 and by a continued fraction otherwise (as in
+// Numerical Recipes)
+func gammaQ(a, x float64) float64 {
+	const (
+		eps   = 1e-15
+		iters = 1000
+		tiny  = 1e-300
+	)
+	if x <= 0 {
+		return 1
+	}
+	lg, _ := math.Lgamma(a)
+	front := math.Exp(-x + a*math.Log(x) - lg)
+	if x < a+1 {
+		sum := 1 / a
+		term := sum
+		for n := 1; n < iters; n++ {
+			term *= x / (a + float64(n))
+			sum += term
+			if math.Abs(term) < math.Abs(sum)*eps {
+				break
+			}
+		}
+		return 1 - sum*front
+	}
+
+	// modified Lentz's method
+	b := x + 1 - a
+	c := 1 / tiny
+	d := 1 / b
+	h := d
+	for n := 1; n < iters; n++ {
+		an := -float64(n) * (float64(n) - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = b + an/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		del := d * c
+		h *= del
+		if math.Abs(del-1) < eps {
+			break
+		}
+	}
+	return front * h
+}
+
+// GradientContinuityTest2 returns the largest ratio |∇n(a)-∇n(b)|/|a-b|
+// over the given number of random pairs of nearby points a and b, with
+// the gradient of Noise2 computed analytically.  Noise2 has a continuous
+// gradient, so the ratio is bounded by the largest Hessian norm of the
+// field.  Each corner adds 70*t^4*(g·d), with t = 0.5-|d|^2, and the
+// worst case over every position and choice of corner gradients is
+// about 55.66, with all three corners of a simplex on gradient (1,1) and
+// the point on its diagonal, 30% of the way from the first corner to
+// the last.  (The bound scales with the output scale, if AutoCalibrate2
+// has changed it.)  A bug in the gradients (or in which corners get
+// picked near a cell boundary) shows up as a much larger ratio.
+func (s *Simplex) GradientContinuityTest2(samples int, r *rand.Rand) (maxDiscontinuity float64) {
+	const step = 1e-3
+	for i := 0; i < samples; i++ {
+		x := r.Float64() * 256
+		y := r.Float64() * 256
+		sin, cos := math.Sincos(r.Float64() * 2 * math.Pi)
+		dx, dy := step*cos, step*sin
+		_, ax, ay := s.noise2Deriv(x, y)
+		_, bx, by := s.noise2Deriv(x+dx, y+dy)
+		d := math.Hypot(ax-bx, ay-by) / math.Hypot(dx, dy)
+		maxDiscontinuity = math.Max(maxDiscontinuity, d)
+	}
+	return maxDiscontinuity
+}
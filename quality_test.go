@@ -0,0 +1,60 @@
+package simplex
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestGammaQ(t *testing.T) {
+	for _, c := range []struct {
+		a, x, q float64
+	}{
+		{0.5, 0.5, 0.3173},   // chi-squared 1 dof, 1.0
+		{1, 2, math.Exp(-2)}, // exponential tail
+		{4.5, 8.4595, 0.05},  // chi-squared 9 dof, 16.919
+		{49.5, 49.5, 0.4811}, // chi-squared 99 dof, 99
+	} {
+		if q := gammaQ(c.a, c.x); math.Abs(q-c.q) > 0.0005 {
+			t.Errorf("Got %.4f for Q(%g, %g), expected %.4f", q, c.a, c.x, c.q)
+		}
+	}
+}
+
+func TestChiSquaredUniformityTest2(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+
+	// the statistic itself, on counts that really are uniform
+	counts := make([]int, 20)
+	for i := 0; i < 1000000; i++ {
+		counts[r.Intn(len(counts))]++
+	}
+	if _, p := chiSquaredUniform(counts); p < 0.05 {
+		t.Errorf("Got p %.4f for uniform counts, expected more than 0.05", p)
+	}
+
+	// raw noise values bunch up around 0, so they are a long way from
+	// uniform, but the same seed must give the same answer
+	n := New(r)
+	chi, p := n.ChiSquaredUniformityTest2(1000000, 20, rand.New(rand.NewSource(101)))
+	chi2, p2 := n.ChiSquaredUniformityTest2(1000000, 20, rand.New(rand.NewSource(101)))
+	if chi != chi2 || p != p2 {
+		t.Errorf("Got %.4f and %.4f from the same samples", chi, chi2)
+	}
+	if chi <= 0 || p < 0 || p > 1 {
+		t.Errorf("Got chi-squared %.4f, p %.4f, expected a positive statistic and p in [0,1]", chi, p)
+	}
+}
+
+func TestGradientContinuityTest2(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	// no pair can beat the largest Hessian norm, about 55.66 (see the
+	// GradientContinuityTest2 doc); a real discontinuity over the 1e-3
+	// step would give a ratio in the thousands
+	const bound = 55.66
+	if d := n.GradientContinuityTest2(1000000, r); d > bound {
+		t.Errorf("Got %.4f, expected at most %.4f", d, bound)
+	}
+}
@@ -0,0 +1,23 @@
+//go:build go1.22
+
+package simplex
+
+import (
+	randv2 "math/rand/v2"
+)
+
+// NewV2 is like New, but takes a math/rand/v2 source, and shuffles the
+// permutation with an unbiased Fisher-Yates shuffle.  Because of both
+// the different generators and the different shuffle, NewV2 does not
+// produce the same noise as New even for the "same" seed.
+func NewV2(r *randv2.Rand) *Simplex {
+	s := &Simplex{}
+	for i := 0; i < 256; i++ {
+		s.mix[i] = uint8(i)
+	}
+	for i := 255; i > 0; i-- {
+		j := r.IntN(i + 1)
+		s.mix[i], s.mix[j] = s.mix[j], s.mix[i]
+	}
+	return s
+}
@@ -0,0 +1,38 @@
+//go:build go1.22
+
+package simplex
+
+import (
+	randv2 "math/rand/v2"
+	"testing"
+)
+
+func TestNewV2(t *testing.T) {
+	n := NewV2(randv2.New(randv2.NewPCG(42, 0)))
+
+	var seen [256]bool
+	for _, p := range n.mix {
+		if seen[p] {
+			t.Fatalf("%d appears twice in the permutation", p)
+		}
+		seen[p] = true
+	}
+
+	r := randv2.New(randv2.NewPCG(101, 0))
+	for i := 0; i < 100000; i++ {
+		x := r.Float64() * 100
+		y := r.Float64() * 100
+		z := r.Float64() * 100
+		w := r.Float64() * 100
+		for _, a := range []float64{n.Noise2(x, y), n.Noise3(x, y, z), n.Noise4(x, y, z, w)} {
+			if a < -1 || a > 1 {
+				t.Fatalf("Got %.4f at (%g,%g,%g,%g), expected value in [-1,1]", a, x, y, z, w)
+			}
+		}
+	}
+
+	again := NewV2(randv2.New(randv2.NewPCG(42, 0)))
+	if again.mix != n.mix {
+		t.Errorf("Got different permutations from the same seed")
+	}
+}
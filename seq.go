@@ -0,0 +1,40 @@
+//go:build go1.23
+
+package simplex
+
+import (
+	"iter"
+)
+
+// Seq1 returns the sequence of Noise1 values at start, start+step,
+// start+2*step, and so on.  The sequence is infinite, so the caller
+// needs to break out of the loop.
+func (s *Simplex) Seq1(start, step float64) iter.Seq[float64] {
+	return func(yield func(float64) bool) {
+		for i := 0; ; i++ {
+			if !yield(s.Noise1(start + float64(i)*step)) {
+				return
+			}
+		}
+	}
+}
+
+// GridSeq2 returns the Noise2 values on a grid width samples wide,
+// starting at (x0, y0) with spacing dx and dy, row by row.  Each value
+// comes with its index row*width+col.  There is no last row, so the
+// caller needs to break out of the loop.
+func (s *Simplex) GridSeq2(x0, y0, dx, dy float64, width int) iter.Seq2[int, float64] {
+	return func(yield func(int, float64) bool) {
+		if width <= 0 {
+			return
+		}
+		for row := 0; ; row++ {
+			y := y0 + float64(row)*dy
+			for col := 0; col < width; col++ {
+				if !yield(row*width+col, s.Noise2(x0+float64(col)*dx, y)) {
+					return
+				}
+			}
+		}
+	}
+}
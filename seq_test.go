@@ -0,0 +1,70 @@
+//go:build go1.23
+
+package simplex
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestSeq1(t *testing.T) {
+	n := New(rand.New(rand.NewSource(101)))
+
+	i := 0
+	for v := range n.Seq1(0.3, 0.01) {
+		if expect := n.Noise1(0.3 + float64(i)*0.01); v != expect {
+			t.Errorf("Got %.4f at step %d, expected %.4f", v, i, expect)
+		}
+		i++
+		if i == 10 {
+			break
+		}
+	}
+	if i != 10 {
+		t.Errorf("Got %d values, expected 10", i)
+	}
+}
+
+func TestSeq1Allocs(t *testing.T) {
+	n := New(rand.New(rand.NewSource(101)))
+
+	var sum float64
+	allocs := testing.AllocsPerRun(100, func() {
+		k := 0
+		for v := range n.Seq1(0, 0.01) {
+			sum += v
+			k++
+			if k == 1000 {
+				break
+			}
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("Got %g allocations per run, expected none", allocs)
+	}
+}
+
+func TestGridSeq2(t *testing.T) {
+	n := New(rand.New(rand.NewSource(101)))
+
+	const width, height = 7, 5
+	seen := make([]bool, width*height)
+	count := 0
+	for i, v := range n.GridSeq2(0.5, -1.5, 0.1, 0.2, width) {
+		if i >= width*height {
+			break
+		}
+		row, col := i/width, i%width
+		if expect := n.Noise2(0.5+float64(col)*0.1, -1.5+float64(row)*0.2); v != expect {
+			t.Errorf("Got %.4f at index %d, expected %.4f", v, i, expect)
+		}
+		if seen[i] {
+			t.Errorf("Index %d produced twice", i)
+		}
+		seen[i] = true
+		count++
+	}
+	if count != width*height {
+		t.Errorf("Got %d samples, expected %d", count, width*height)
+	}
+}
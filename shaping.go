@@ -0,0 +1,45 @@
+package simplex
+
+import (
+	"math"
+)
+
+// TerraceNoise quantizes a noise value v in [-1,1] into terraces evenly
+// spaced plateaus from -1 to 1, for stepped (mesa) terrain.  Each step
+// edge is softened with smoothstep over smoothing (a fraction of the
+// width of a step, from 0 for hard edges to 1 for no flat parts at
+// all).  With fewer than two terraces there is nothing to quantize and
+// v is returned unchanged.
+func TerraceNoise(v float64, terraces int, smoothing float64) float64 {
+	if terraces < 2 {
+		return v
+	}
+	steps := float64(terraces)
+	level := func(k float64) float64 {
+		return -1 + 2*k/(steps-1)
+	}
+
+	u := (v + 1) / 2 * steps
+	k := math.Max(0, math.Min(math.Floor(u), steps-1))
+
+	// the nearest step edge
+	e := math.Max(1, math.Min(math.Round(u), steps-1))
+	if d := u - e; smoothing > 0 && math.Abs(d) < smoothing/2 {
+		return level(e-1) + (level(e)-level(e-1))*smoothstep(d/smoothing+0.5)
+	}
+	return level(k)
+}
+
+// IslandMask is 1 within innerRadius of (cx, cy), 0 beyond outerRadius,
+// and falls off smoothly (with zero slope at both ends) in between.
+// Multiplying a height map by it sinks everything but the middle.
+func IslandMask(x, y, cx, cy, innerRadius, outerRadius float64) float64 {
+	d := math.Hypot(x-cx, y-cy)
+	if d <= innerRadius {
+		return 1
+	}
+	if d >= outerRadius {
+		return 0
+	}
+	return 1 - smoothstep((d-innerRadius)/(outerRadius-innerRadius))
+}
@@ -0,0 +1,97 @@
+package simplex
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestTerraceNoise(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+
+	plateaus := map[float64]int{}
+	for i := 0; i < 100000; i++ {
+		v := r.Float64()*2 - 1
+		a := TerraceNoise(v, 5, 0)
+		plateaus[a]++
+		// a hard step is monotonic
+		if b := TerraceNoise(v+0.01, 5, 0); b < a {
+			t.Fatalf("got %.4f at %.4f but %.4f just above", a, v, b)
+		}
+	}
+	if len(plateaus) != 5 {
+		t.Errorf("got %d distinct values with hard steps, expected 5", len(plateaus))
+	}
+	for _, p := range []float64{-1, -0.5, 0, 0.5, 1} {
+		if plateaus[p] == 0 {
+			t.Errorf("plateau %.4f never came up", p)
+		}
+	}
+	if a := TerraceNoise(1, 5, 0); a != 1 {
+		t.Errorf("got %.4f at the top, expected 1", a)
+	}
+	if a := TerraceNoise(-1, 5, 0); a != -1 {
+		t.Errorf("got %.4f at the bottom, expected -1", a)
+	}
+
+	// soft edges only touch a small part of the range, and are smooth
+	flat := 0
+	for i := 0; i < 100000; i++ {
+		v := r.Float64()*2 - 1
+		a := TerraceNoise(v, 5, 0.1)
+		if math.Abs(a-TerraceNoise(v, 5, 0)) < 1e-12 {
+			flat++
+		}
+		if b := TerraceNoise(v+1e-6, 5, 0.1); math.Abs(b-a) > 1e-4 {
+			t.Fatalf("got a jump from %.6f to %.6f at %.6f", a, b, v)
+		}
+	}
+	if flat < 88000 {
+		t.Errorf("got %d samples on a plateau out of 100000, expected about 92000", flat)
+	}
+}
+
+func TestIslandMask(t *testing.T) {
+	const cx, cy = 3, -2
+	const inner, outer = 10, 25
+
+	for _, c := range []struct{ d, expect float64 }{
+		{0, 1}, {5, 1}, {10, 1}, {25, 0}, {40, 0}, {17.5, 0.5},
+	} {
+		if a := IslandMask(cx+c.d*0.6, cy-c.d*0.8, cx, cy, inner, outer); math.Abs(a-c.expect) > 1e-9 {
+			t.Errorf("got %.4f at distance %g, expected %.4f", a, c.d, c.expect)
+		}
+	}
+
+	// flat at both edges of the transition, and downhill in between
+	const e = 1e-4
+	for _, d := range []float64{inner, outer} {
+		slope := (IslandMask(cx+d+e, cy, cx, cy, inner, outer) - IslandMask(cx+d-e, cy, cx, cy, inner, outer)) / (2 * e)
+		if math.Abs(slope) > 1e-3 {
+			t.Errorf("got slope %.6f at distance %g, expected 0", slope, d)
+		}
+	}
+	for d := inner + 0.5; d < outer; d += 0.5 {
+		if IslandMask(cx+d, cy, cx, cy, inner, outer) <= IslandMask(cx+d+0.5, cy, cx, cy, inner, outer) {
+			t.Errorf("mask does not fall off at distance %g", d)
+		}
+	}
+
+	// masking a noise height map leaves land only near the middle
+	n := New(rand.New(rand.NewSource(101)))
+	land := 0
+	for y := -30.0; y < 30; y++ {
+		for x := -30.0; x < 30; x++ {
+			h := Normalize(n.FBM2(x*0.05, y*0.05, 4, 2, 0.5)) * IslandMask(x, y, 0, 0, 12, 28)
+			if h > 0.3 {
+				land++
+				if math.Hypot(x, y) >= 28 {
+					t.Fatalf("got land at (%g,%g), outside the island", x, y)
+				}
+			}
+		}
+	}
+	if land < 200 {
+		t.Errorf("got %d land cells, expected an island", land)
+	}
+}
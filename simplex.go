@@ -33,17 +33,37 @@ package simplex
  */
 
 import (
+	"hash/fnv"
 	"math"
 	"math/rand"
 )
 
+// A Simplex is a noise source.  The noise functions don't modify it, so
+// one can be shared between goroutines.  Reset and AutoCalibrate2 do
+// modify it, and must not run while any other goroutine is using it;
+// SyncSimplex provides locked versions of them.
 type Simplex struct {
 	// this is a permutation of the numbers 0-255
 	mix [256]uint8
+	// output scale for Noise2, if set by AutoCalibrate2
+	scale2 float64
 }
 
 func New(r *rand.Rand) *Simplex {
 	s := &Simplex{}
+	s.shuffle(r)
+	return s
+}
+
+// Reset re-randomizes s in place from r, leaving it just as New(r)
+// would have made it (including dropping any AutoCalibrate2 scaling).
+// It must not run while other goroutines are using s.
+func (s *Simplex) Reset(r *rand.Rand) {
+	*s = Simplex{}
+	s.shuffle(r)
+}
+
+func (s *Simplex) shuffle(r *rand.Rand) {
 	// initialize it
 	for i := 0; i < 256; i++ {
 		s.mix[i] = uint8(i)
@@ -55,7 +75,25 @@ func New(r *rand.Rand) *Simplex {
 			s.mix[i], s.mix[j] = s.mix[j], s.mix[i]
 		}
 	}
-	return s
+}
+
+// NewFromSeed is a shorthand for New(rand.New(rand.NewSource(seed)))
+func NewFromSeed(seed int64) *Simplex {
+	return New(rand.New(rand.NewSource(seed)))
+}
+
+// NewFromString builds a noise source keyed by a string, which is
+// hashed with FNV-64a to produce the seed.
+func NewFromString(key string) *Simplex {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	return NewFromSeed(int64(h.Sum64()))
+}
+
+// Equal reports whether a and b have the same permutation table, and so
+// give the same noise (apart from any scaling set by AutoCalibrate2)
+func Equal(a, b *Simplex) bool {
+	return a.mix == b.mix
 }
 
 type grad2 struct {
@@ -122,27 +160,121 @@ func fastfloor(x float64) int {
 	return int(math.Floor(x))
 }
 
-var F2 = 0.5 * (math.Sqrt(3.0) - 1.0)
-var G2 = (3.0 - math.Sqrt(3.0)) / 6.0
+// finite reports whether x is neither NaN nor infinite.  Converting a
+// non-finite value to an int (as in fastfloor) is implementation
+// defined, so the noise functions check for them up front.
+func finite(x float64) bool {
+	return !math.IsNaN(x) && !math.IsInf(x, 0)
+}
+
+// beyond this, the noise functions use skew to find the simplex cell
+// instead of the plain calculation, which is faster but loses precision
+const largeCoord = 1 << 24
+
+// skew finds the simplex cell containing the first n coordinates of c
+// and the offsets of c from the cell origin, using skew factor f and
+// unskew factor g.  The integer and fractional parts of the input are
+// kept apart (and the rounding error of the big products is carried
+// along) so that the offsets stay accurate even for coordinates in the
+// 1e14 range, where computing x+(x+y)*F2 directly would swamp the
+// fractional part of x.
+func skew(c *[4]float64, n int, f, g float64) (cell [4]int, d [4]float64) {
+	var ip, fp [4]float64
+	var si, sf float64
+	for k := 0; k < n; k++ {
+		ip[k], fp[k] = math.Modf(c[k])
+		si += ip[k]
+		sf += fp[k]
+	}
+	// si*f = pi + pf + pe exactly
+	p := si * f
+	pe := math.FMA(si, f, -p)
+	pi, pf := math.Modf(p)
+	frac := pf + pe + sf*f
+
+	var fl [4]float64
+	sum := 0
+	for k := 0; k < n; k++ {
+		fl[k] = math.Floor(fp[k] + frac)
+		cell[k] = int(ip[k]) + int(pi) + int(fl[k])
+		sum += cell[k]
+	}
+	// sum*g = q + qe exactly, and q-p is exact since they are close
+	q := float64(sum) * g
+	qe := math.FMA(float64(sum), g, -q)
+	u := (q - p) + pf + qe
+	for k := 0; k < n; k++ {
+		d[k] = fp[k] - fl[k] + u
+	}
+	return
+}
+
+// Skewing and unskewing factors for 2, 3 and 4 dimensions.  FN is
+// (sqrt(N+1)-1)/N and GN is (1-1/sqrt(N+1))/N; they are written out
+// (to more digits than a float64 holds) so they can be constants.
+const (
+	F2 = 0.3660254037844386467637231707529361834715 // (sqrt(3)-1)/2
+	G2 = 0.2113248654051871177454256097490212721762 // (3-sqrt(3))/6
+	F3 = 1.0 / 3.0
+	G3 = 1.0 / 6.0
+	F4 = 0.3090169943749474241022934171828190588602 // (sqrt(5)-1)/4
+	G4 = 0.1381966011250105151795413165634361882280 // (5-sqrt(5))/20
+)
+
+// Noise1 returns 1D simplex noise at x, in [-1,1].  This follows
+// Gustavson's C++ version, since the Java code has no 1D case; the
+// gradients are the integers -8..8 (excluding 0).
+func (s *Simplex) Noise1(x float64) float64 {
+	if !finite(x) {
+		return math.NaN()
+	}
+	i := fastfloor(x)
+	x0 := x - float64(i)
+	x1 := x0 - 1
+
+	grad := func(hash int, x float64) float64 {
+		g := float64(1 + hash&7)
+		if hash&8 != 0 {
+			g = -g
+		}
+		return g * x
+	}
+
+	t0 := 1 - x0*x0
+	t0 *= t0
+	n0 := t0 * t0 * grad(s.getPerm(i), x0)
 
-const F3 = 1.0 / 3.0
-const G3 = 1.0 / 6.0
+	t1 := 1 - x1*x1
+	t1 *= t1
+	n1 := t1 * t1 * grad(s.getPerm(i+1), x1)
 
-var F4 = (math.Sqrt(5.0) - 1.0) / 4.0
-var G4 = (5.0 - math.Sqrt(5.0)) / 20.0
+	// scale the result to fit into [-1,1]
+	return 0.395 * (n0 + n1)
+}
 
 func (s *Simplex) Noise2(x, y float64) float64 {
+	if !finite(x) || !finite(y) {
+		return math.NaN()
+	}
 	//double n0, n1, n2; // Noise contributions from the three corners
 	// Skew the input space to determine which simplex cell we're in
-	h := (x + y) * F2 // Hairy factor for 2D
-	i := fastfloor(x + h)
-	j := fastfloor(y + h)
-	t := float64(i+j) * G2
-
-	X0 := float64(i) - t // Unskew the cell origin back to (x,y) space
-	Y0 := float64(j) - t
-	x0 := x - float64(X0) // The x,y distances from the cell origin
-	y0 := y - float64(Y0)
+	var i, j int
+	var x0, y0 float64
+	if math.Abs(x) < largeCoord && math.Abs(y) < largeCoord {
+		h := (x + y) * F2 // Hairy factor for 2D
+		i = fastfloor(x + h)
+		j = fastfloor(y + h)
+		t := float64(i+j) * G2
+
+		X0 := float64(i) - t // Unskew the cell origin back to (x,y) space
+		Y0 := float64(j) - t
+		x0 = x - float64(X0) // The x,y distances from the cell origin
+		y0 = y - float64(Y0)
+	} else {
+		cell, d := skew(&[4]float64{x, y}, 2, F2, G2)
+		i, j = cell[0], cell[1]
+		x0, y0 = d[0], d[1]
+	}
 
 	//log.Printf("X (%d,%d) x (%g,%g)", X0, Y0, x0, y0)
 
@@ -198,26 +330,65 @@ func (s *Simplex) Noise2(x, y float64) float64 {
 	}
 	// Add contributions from each corner to get the final noise value.
 	// The result is scaled to return values in the interval [-1,1].
-	return 70.0 * (n0 + n1 + n2)
+	return s.noise2Scale() * (n0 + n1 + n2)
+}
+
+// noise2Scale is the factor Noise2 applies to the sum of the corner
+// contributions
+func (s *Simplex) noise2Scale() float64 {
+	if s.scale2 != 0 {
+		return s.scale2
+	}
+	return 70.0
+}
+
+// AutoCalibrate2 sets the output scale of Noise2 so that the largest
+// value seen over the given number of random samples just reaches 1
+// (less a 0.1% margin, since the largest sample will fall a little
+// short of the true maximum).  The fixed scale of 70 from the original
+// code tops out a little under 1.  It must not run while other
+// goroutines are using s.
+func (s *Simplex) AutoCalibrate2(samples int, r *rand.Rand) {
+	scale := s.noise2Scale()
+	var peak float64
+	for i := 0; i < samples; i++ {
+		// the lattice hashing repeats every 256 cells, so this covers
+		// every case
+		peak = math.Max(peak, math.Abs(s.Noise2(r.Float64()*256, r.Float64()*256)))
+	}
+	if peak > 0 {
+		s.scale2 = scale / peak / 1.001
+	}
 }
 
 func (s *Simplex) Noise3(x, y, z float64) float64 {
+	if !finite(x) || !finite(y) || !finite(z) {
+		return math.NaN()
+	}
 	//double n0, n1, n2, n3; // Noise contributions from the four corners
 	// Skew the input space to determine which simplex cell we're in
-	h := (x + y + z) * F3 // Very nice and simple skew factor for 3D
-
-	i := fastfloor(x + h)
-	j := fastfloor(y + h)
-	k := fastfloor(z + h)
-
-	t := float64(i+j+k) * G3
-	X0 := float64(i) - t // Unskew the cell origin back to (x,y,z) space
-	Y0 := float64(j) - t
-	Z0 := float64(k) - t
-
-	x0 := x - float64(X0) // The x,y,z distances from the cell origin
-	y0 := y - float64(Y0)
-	z0 := z - float64(Z0)
+	var i, j, k int
+	var x0, y0, z0 float64
+	if math.Abs(x) < largeCoord && math.Abs(y) < largeCoord && math.Abs(z) < largeCoord {
+		h := (x + y + z) * F3 // Very nice and simple skew factor for 3D
+
+		i = fastfloor(x + h)
+		j = fastfloor(y + h)
+		k = fastfloor(z + h)
+
+		t := float64(i+j+k) * G3
+		X0 := float64(i) - t // Unskew the cell origin back to (x,y,z) space
+		Y0 := float64(j) - t
+		Z0 := float64(k) - t
+
+		x0 = x - float64(X0) // The x,y,z distances from the cell origin
+		y0 = y - float64(Y0)
+		z0 = z - float64(Z0)
+	} else {
+		cell, d := skew(&[4]float64{x, y, z}, 3, F3, G3)
+		i, j, k = cell[0], cell[1], cell[2]
+		x0, y0, z0 = d[0], d[1], d[2]
+	}
 
 	// For the 3D case, the simplex shape is a slightly irregular tetrahedron.
 	// Determine which simplex we are in.
@@ -335,21 +506,32 @@ func ifexpr(cond bool, t, f int) int {
 }
 
 func (s *Simplex) Noise4(x, y, z, w float64) float64 {
+	if !finite(x) || !finite(y) || !finite(z) || !finite(w) {
+		return math.NaN()
+	}
 	// Skew the (x,y,z,w) space to determine which cell of 24 simplices we're in
-	h := (x + y + z + w) * F4 // Factor for 4D skewing
-	i := fastfloor(x + h)
-	j := fastfloor(y + h)
-	k := fastfloor(z + h)
-	l := fastfloor(w + h)
-	t := float64(i+j+k+l) * G4 // Factor for 4D unskewing
-	X0 := float64(i) - t       // Unskew the cell origin back to (x,y,z,w) space
-	Y0 := float64(j) - t
-	Z0 := float64(k) - t
-	W0 := float64(l) - t
-	x0 := x - float64(X0) // The x,y,z,w distances from the cell origin
-	y0 := y - float64(Y0)
-	z0 := z - float64(Z0)
-	w0 := w - float64(W0)
+	var i, j, k, l int
+	var x0, y0, z0, w0 float64
+	if math.Abs(x) < largeCoord && math.Abs(y) < largeCoord && math.Abs(z) < largeCoord && math.Abs(w) < largeCoord {
+		h := (x + y + z + w) * F4 // Factor for 4D skewing
+		i = fastfloor(x + h)
+		j = fastfloor(y + h)
+		k = fastfloor(z + h)
+		l = fastfloor(w + h)
+		t := float64(i+j+k+l) * G4 // Factor for 4D unskewing
+		X0 := float64(i) - t       // Unskew the cell origin back to (x,y,z,w) space
+		Y0 := float64(j) - t
+		Z0 := float64(k) - t
+		W0 := float64(l) - t
+		x0 = x - float64(X0) // The x,y,z,w distances from the cell origin
+		y0 = y - float64(Y0)
+		z0 = z - float64(Z0)
+		w0 = w - float64(W0)
+	} else {
+		cell, d := skew(&[4]float64{x, y, z, w}, 4, F4, G4)
+		i, j, k, l = cell[0], cell[1], cell[2], cell[3]
+		x0, y0, z0, w0 = d[0], d[1], d[2], d[3]
+	}
 	// For the 4D case, the simplex is a 4D shape I won't even try to describe.
 	// To find out which of the 24 possible simplices we're in, we need to
 	// determine the magnitude ordering of x0, y0, z0 and w0.
@@ -489,3 +671,8 @@ func (s *Simplex) Noise4(x, y, z, w float64) float64 {
 	// Sum up and scale the result to cover the range [-1,1]
 	return 27.0 * (n0 + n1 + n2 + n3 + n4)
 }
+
+// Normalize maps a noise value from [-1,1] to [0,1]
+func Normalize(v float64) float64 {
+	return (v + 1) / 2
+}
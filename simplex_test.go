@@ -1,6 +1,7 @@
 package simplex
 
 import (
+	"fmt"
 	"math"
 	"math/rand"
 	"testing"
@@ -112,6 +113,131 @@ func TestSimplex4(t *testing.T) {
 	}
 }
 
+func TestNonFinite(t *testing.T) {
+	n := New(rand.New(rand.NewSource(101)))
+
+	for _, bad := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		if a := n.Noise1(bad); !math.IsNaN(a) {
+			t.Errorf("Noise1(%g) = %g, expected NaN", bad, a)
+		}
+		if a := n.Noise2(bad, 0); !math.IsNaN(a) {
+			t.Errorf("Noise2(%g, 0) = %g, expected NaN", bad, a)
+		}
+		if a := n.Noise2(0, bad); !math.IsNaN(a) {
+			t.Errorf("Noise2(0, %g) = %g, expected NaN", bad, a)
+		}
+		if a := n.Noise3(bad, 0, 0); !math.IsNaN(a) {
+			t.Errorf("Noise3(%g, 0, 0) = %g, expected NaN", bad, a)
+		}
+		if a := n.Noise3(0, 0, bad); !math.IsNaN(a) {
+			t.Errorf("Noise3(0, 0, %g) = %g, expected NaN", bad, a)
+		}
+		for k := 0; k < 4; k++ {
+			var p [4]float64
+			p[k] = bad
+			if a := n.Noise4(p[0], p[1], p[2], p[3]); !math.IsNaN(a) {
+				t.Errorf("Noise4%v = %g, expected NaN", p, a)
+			}
+		}
+	}
+}
+
+func TestNoise1(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	for i := 0; i < 100000; i++ {
+		x := r.Float64() * 1000
+		if a := n.Noise1(x); a < -1 || a > 1 {
+			t.Fatalf("Got %.4f at %g, expected value in [-1,1]", a, x)
+		}
+	}
+	if a := n.Noise1(17); a != 0 {
+		t.Errorf("Got %.4f at a lattice point, expected 0", a)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	for _, c := range [][2]float64{{-1, 0}, {0, 0.5}, {1, 1}, {0.5, 0.75}} {
+		if a := Normalize(c[0]); a != c[1] {
+			t.Errorf("Got %.4f, expected %.4f", a, c[1])
+		}
+	}
+}
+
+func TestAutoCalibrate2(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	n.AutoCalibrate2(1000000, r)
+	var peak float64
+	for i := 0; i < 10000000; i++ {
+		x := r.Float64()*2000 - 1000
+		y := r.Float64()*2000 - 1000
+		a := n.Noise2(x, y)
+		if a < -1 || a > 1 {
+			t.Fatalf("Got %.6f at (%g,%g) after calibration, expected value in [-1,1]", a, x, y)
+		}
+		peak = math.Max(peak, math.Abs(a))
+	}
+	if peak < 0.99 {
+		t.Errorf("Got peak %.4f after calibration, expected close to 1", peak)
+	}
+}
+
+func TestSkewConstants(t *testing.T) {
+	for _, c := range []struct {
+		name         string
+		value, exact float64
+	}{
+		{"F2", F2, 0.5 * (math.Sqrt(3) - 1)},
+		{"G2", G2, (3 - math.Sqrt(3)) / 6},
+		{"F3", F3, (math.Sqrt(4) - 1) / 3},
+		{"G3", G3, (4 - math.Sqrt(4)) / 12},
+		{"F4", F4, (math.Sqrt(5) - 1) / 4},
+		{"G4", G4, (5 - math.Sqrt(5)) / 20},
+	} {
+		if math.Abs(c.value-c.exact) > 2e-16 {
+			t.Errorf("Got %s = %.17g, expected %.17g", c.name, c.value, c.exact)
+		}
+	}
+}
+
+func TestLargeCoordinates(t *testing.T) {
+	n := New(rand.New(rand.NewSource(101)))
+
+	// (1e15+0.1 and 1e15+0.11 are the same float64, so test at 1e14)
+	a := n.Noise2(1e14+0.1, 0)
+	b := n.Noise2(1e14+0.11, 0)
+	if a == b {
+		t.Errorf("Got %.4f for both, expected different values", a)
+	}
+	if a < -1 || a > 1 || b < -1 || b > 1 {
+		t.Errorf("Got %.4f and %.4f, expected values in [-1,1]", a, b)
+	}
+
+	// stepping one ulp at a time should look like a smooth function
+	// sampled with that step, not a staircase
+	for _, base := range []float64{1e8, 1e12, 1e14} {
+		x := base
+		prev2, prev3, prev4 := n.Noise2(x, 0.3), n.Noise3(x, 0.3, 0.7), n.Noise4(x, 0.3, 0.7, 0.1)
+		var worst float64
+		for i := 0; i < 200; i++ {
+			next := math.Nextafter(x, math.Inf(1))
+			step := next - x
+			x = next
+			a2, a3, a4 := n.Noise2(x, 0.3), n.Noise3(x, 0.3, 0.7), n.Noise4(x, 0.3, 0.7, 0.1)
+			for _, d := range []float64{a2 - prev2, a3 - prev3, a4 - prev4} {
+				worst = math.Max(worst, math.Abs(d)/step)
+			}
+			prev2, prev3, prev4 = a2, a3, a4
+		}
+		if worst > 5 {
+			t.Errorf("Got slope %.4f near %g, expected at most %.4f", worst, base, 5.0)
+		}
+	}
+}
+
 // on my machine (charon) we get about 145 ns/op
 func BenchmarkSimplex(b *testing.B) {
 	r := rand.New(rand.NewSource(101))
@@ -125,3 +251,114 @@ func BenchmarkSimplex(b *testing.B) {
 		y += 0.00000012
 	}
 }
+
+func TestNewFromString(t *testing.T) {
+	a := NewFromString("hello").Noise2(0.3, 1.25)
+	b := NewFromString("hello").Noise2(0.3, 1.25)
+	if a != b {
+		t.Errorf("got %.4f and %.4f from the same key", a, b)
+	}
+
+	// empty key is fine too
+	NewFromString("").Noise2(0.3, 1.25)
+
+	sample := func(s *Simplex) [4]float64 {
+		return [4]float64{
+			s.Noise2(0.3, 1.25),
+			s.Noise2(10.7, -3.2),
+			s.Noise2(-41.1, 7.9),
+			s.Noise2(100.5, 200.25),
+		}
+	}
+
+	seen := make(map[[4]float64]int)
+	for i := 0; i < 1000; i++ {
+		cur := sample(NewFromString(fmt.Sprintf("key-%d", i)))
+		if j, ok := seen[cur]; ok {
+			t.Errorf("got identical outputs from key-%d and key-%d", j, i)
+		}
+		seen[cur] = i
+	}
+}
+
+func TestEqual(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+
+	s := NewFromSeed(101)
+	if !Equal(s, s) {
+		t.Errorf("got a Simplex unequal to itself")
+	}
+	if !Equal(s, NewFromSeed(101)) || !Equal(NewFromSeed(101), s) {
+		t.Errorf("got unequal Simplexes from the same seed")
+	}
+
+	same := 0
+	for i := 0; i < 1000; i++ {
+		sa, sb := r.Int63(), r.Int63()
+		if sa == sb {
+			continue
+		}
+		a, b := NewFromSeed(sa), NewFromSeed(sb)
+		if Equal(a, b) != Equal(b, a) {
+			t.Fatalf("got Equal(a, b) = %v but Equal(b, a) = %v", Equal(a, b), Equal(b, a))
+		}
+		if Equal(a, b) {
+			same++
+		}
+	}
+	if same > 0 {
+		t.Errorf("got %d equal pairs out of 1000 distinct seeds", same)
+	}
+}
+
+func TestReset(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+
+	for _, s := range []*Simplex{NewFromSeed(7), New(r), &Simplex{}} {
+		s.AutoCalibrate2(1000, r)
+		s.Reset(rand.New(rand.NewSource(101)))
+		fresh := New(rand.New(rand.NewSource(101)))
+		if !Equal(s, fresh) {
+			t.Fatalf("got a different permutation after Reset")
+		}
+		if a, b := s.Noise2(0, 1.25), fresh.Noise2(0, 1.25); a != b {
+			t.Errorf("Got %.4f, expected %.4f", a, b)
+		}
+	}
+}
+
+// expect about 1.5 times BenchmarkSimplex, and no allocations
+func BenchmarkNoise3(b *testing.B) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	b.ReportAllocs()
+	x := 0.001
+	y := 0.0001
+	z := 0.00001
+	for i := 0; i < b.N; i++ {
+		n.Noise3(x, y, z)
+		x += 0.00000011
+		y += 0.00000012
+		z += 0.00000013
+	}
+}
+
+// expect about twice BenchmarkSimplex, and no allocations
+func BenchmarkNoise4(b *testing.B) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	b.ReportAllocs()
+	x := 0.001
+	y := 0.0001
+	z := 0.00001
+	w := 0.000001
+	for i := 0; i < b.N; i++ {
+		n.Noise4(x, y, z, w)
+		x += 0.00000011
+		y += 0.00000012
+		z += 0.00000013
+		w += 0.00000014
+	}
+}
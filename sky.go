@@ -0,0 +1,56 @@
+package simplex
+
+import (
+	"math"
+)
+
+// SkyGradient2 returns the sky color (each component in [0,1]) at
+// (x, y), where y is the height above the horizon in [0,1].  The
+// timeOfDay runs from 0 (midnight) through 0.5 (noon) to 1 (midnight
+// again).  The clear sky blends from a night palette to a day palette
+// as the sun rises, with a warm band at the horizon around sunrise and
+// sunset.  Clouds drift as the day goes by: their time axis walks a
+// circle through the third and fourth dimensions of Noise4, so midnight
+// at 1 gives the same clouds as midnight at 0.
+func SkyGradient2(s *Simplex, x, y, timeOfDay float64) (r, g, b float64) {
+	sun := -math.Cos(2 * math.Pi * timeOfDay) // -1 at midnight, 1 at noon
+	day := smoothstep((sun + 0.2) / 0.6)
+	h := clamp01(y)
+
+	// clear sky: lighter at the horizon, deeper overhead
+	r = lerp(lerp(0.05, 0.01, h), lerp(0.7, 0.25, h), day)
+	g = lerp(lerp(0.05, 0.01, h), lerp(0.8, 0.45, h), day)
+	b = lerp(lerp(0.12, 0.05, h), lerp(1.0, 0.9, h), day)
+
+	// twilight glow near the horizon when the sun is low
+	glow := math.Max(0, 1-math.Abs(sun)/0.3) * (1 - h) * (1 - h)
+	r = lerp(r, 1.0, 0.8*glow)
+	g = lerp(g, 0.5, 0.8*glow)
+	b = lerp(b, 0.2, 0.8*glow)
+
+	// a circle of circumference 1, so the clouds move about as fast as
+	// they would with timeOfDay as a plain third coordinate
+	sin, cos := math.Sincos(2 * math.Pi * timeOfDay)
+	const radius = 1 / (2 * math.Pi)
+	cloud := smoothstep((s.Noise4(x, y, radius*cos, radius*sin) - 0.1) / 0.5)
+	shade := lerp(0.15, 0.95, day)
+	r = lerp(r, shade, cloud)
+	g = lerp(g, shade, cloud)
+	b = lerp(b, shade, cloud)
+
+	return clamp01(r), clamp01(g), clamp01(b)
+}
+
+func lerp(a, b, t float64) float64 {
+	return a + (b-a)*t
+}
+
+func clamp01(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
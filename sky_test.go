@@ -0,0 +1,38 @@
+package simplex
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestSkyGradient2(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	var noon, midnight float64
+	for i := 0; i < 10000; i++ {
+		x := r.Float64() * 10
+		y := r.Float64()
+		tod := r.Float64()
+		cr, cg, cb := SkyGradient2(n, x, y, tod)
+		for _, c := range []float64{cr, cg, cb} {
+			if c < 0 || c > 1 {
+				t.Fatalf("got color (%.4f, %.4f, %.4f), expected components in [0,1]", cr, cg, cb)
+			}
+		}
+
+		cr, cg, cb = SkyGradient2(n, x, y, 1)
+		if r0, g0, b0 := SkyGradient2(n, x, y, 0); math.Abs(cr-r0)+math.Abs(cg-g0)+math.Abs(cb-b0) > 1e-9 {
+			t.Fatalf("got (%.4f, %.4f, %.4f) at time 1, expected (%.4f, %.4f, %.4f) as at time 0", cr, cg, cb, r0, g0, b0)
+		}
+
+		cr, cg, cb = SkyGradient2(n, x, y, 0.5)
+		noon += cr + cg + cb
+		cr, cg, cb = SkyGradient2(n, x, y, 0)
+		midnight += cr + cg + cb
+	}
+	if noon <= 2*midnight {
+		t.Errorf("got total brightness %.1f at noon and %.1f at midnight, expected noon much brighter", noon, midnight)
+	}
+}
@@ -0,0 +1,44 @@
+package simplex
+
+import (
+	"math/rand"
+)
+
+// noiseSource is a rand.Source that walks along a line through the
+// noise field, one step per value
+type noiseSource struct {
+	s            *Simplex
+	x0, y0       float64
+	stepX, stepY float64
+	x, y         float64
+}
+
+// RandSource returns a rand.Source whose values come from sampling
+// Noise2 at (x0, y0), then (x0+stepX, y0+stepY), and so on.  The values
+// are only as random as the noise is, so this is meant for cheap
+// reproducible jitter rather than anything statistical.
+func (s *Simplex) RandSource(x0, y0, stepX, stepY float64) rand.Source {
+	return &noiseSource{
+		s:     s,
+		x0:    x0,
+		y0:    y0,
+		stepX: stepX,
+		stepY: stepY,
+		x:     x0,
+		y:     y0,
+	}
+}
+
+// Int63 returns the next value, in [0,1<<63)
+func (src *noiseSource) Int63() int64 {
+	u := noiseUniform(src.s.Noise2(src.x, src.y))
+	src.x += src.stepX
+	src.y += src.stepY
+	return int64(u * (1 << 63))
+}
+
+// Seed restarts the sequence, seed steps past the starting point
+func (src *noiseSource) Seed(seed int64) {
+	src.x = src.x0 + float64(seed)*src.stepX
+	src.y = src.y0 + float64(seed)*src.stepY
+}
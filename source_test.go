@@ -0,0 +1,32 @@
+package simplex
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestRandSource(t *testing.T) {
+	n := New(rand.New(rand.NewSource(101)))
+
+	var _ rand.Source = n.RandSource(0, 0, 0.1, 0)
+
+	r := rand.New(n.RandSource(0, 0, 0.1, 0))
+	for i := 0; i < 10000; i++ {
+		if f := r.Float64(); f < 0 || f >= 1 {
+			t.Fatalf("Got %.4f, expected value in [0,1)", f)
+		}
+	}
+
+	a := n.RandSource(0.5, 0.25, 0.1, 0.05)
+	b := n.RandSource(0.5, 0.25, 0.1, 0.05)
+	for i := 0; i < 100; i++ {
+		if x, y := a.Int63(), b.Int63(); x != y {
+			t.Fatalf("Got %d and %d at step %d, expected the same", x, y, i)
+		}
+	}
+
+	a.Seed(0)
+	if x, y := a.Int63(), n.RandSource(0.5, 0.25, 0.1, 0.05).Int63(); x != y {
+		t.Errorf("Got %d after Seed(0), expected %d", x, y)
+	}
+}
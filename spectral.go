@@ -0,0 +1,94 @@
+package simplex
+
+import (
+	"math"
+)
+
+// SpectralSynthesis2 returns a size x size grid, normalized to [-1,1],
+// whose power spectrum falls off as 1/f^spectralExponent (0 is white
+// noise, 2 is Brownian, 3 is a typical terrain).  It is built in the
+// frequency domain: every frequency gets amplitude f^(-spectralExponent/2)
+// and a random phase, hashed through the permutation table, and the
+// grid is the real part of the inverse transform.  The grid wraps
+// around at its edges.  The phase hashing repeats every 256 steps of
+// frequency, so grids much over 256 on a side show some regularity.
+// The transform is a plain separable DFT, so this takes time
+// proportional to size^3.
+func (s *Simplex) SpectralSynthesis2(size int, spectralExponent float64) *Grid2D {
+	if size <= 0 {
+		return NewGrid2D(0, 0)
+	}
+	re := make([]float64, size*size)
+	im := make([]float64, size*size)
+	for v := 0; v < size; v++ {
+		for u := 0; u < size; u++ {
+			if u == 0 && v == 0 {
+				continue
+			}
+			f := math.Hypot(float64(signedFreq(u, size)), float64(signedFreq(v, size)))
+			amp := math.Pow(f, -spectralExponent/2)
+			a := s.getPerm(u + s.getPerm(v))
+			b := s.getPerm(a + v + s.getPerm(u+1))
+			phase := 2 * math.Pi * (float64(a)*256 + float64(b) + 0.5) / 65536
+			sin, cos := math.Sincos(phase)
+			re[u+v*size] = amp * cos
+			im[u+v*size] = amp * sin
+		}
+	}
+	dft2(re, im, size, true)
+	return (&Grid2D{Data: re, Width: size, Height: size}).Normalize()
+}
+
+// signedFreq returns the frequency of DFT index k of n, in (-n/2,n/2]
+func signedFreq(k, n int) int {
+	if k > n/2 {
+		return k - n
+	}
+	return k
+}
+
+// dft2 transforms the n x n complex grid (re, im), in place, along its
+// rows and then its columns.  The inverse transform is not scaled by
+// 1/n^2.
+func dft2(re, im []float64, n int, inverse bool) {
+	sign := -1.0
+	if inverse {
+		sign = 1
+	}
+	cos := make([]float64, n)
+	sin := make([]float64, n)
+	for k := range cos {
+		sin[k], cos[k] = math.Sincos(sign * 2 * math.Pi * float64(k) / float64(n))
+	}
+
+	rowRe := make([]float64, n)
+	rowIm := make([]float64, n)
+	outRe := make([]float64, n)
+	outIm := make([]float64, n)
+	dft := func(at func(i int) int) {
+		for i := 0; i < n; i++ {
+			rowRe[i] = re[at(i)]
+			rowIm[i] = im[at(i)]
+		}
+		for k := 0; k < n; k++ {
+			var sr, si float64
+			for i := 0; i < n; i++ {
+				t := (i * k) % n
+				sr += rowRe[i]*cos[t] - rowIm[i]*sin[t]
+				si += rowRe[i]*sin[t] + rowIm[i]*cos[t]
+			}
+			outRe[k] = sr
+			outIm[k] = si
+		}
+		for i := 0; i < n; i++ {
+			re[at(i)] = outRe[i]
+			im[at(i)] = outIm[i]
+		}
+	}
+	for y := 0; y < n; y++ {
+		dft(func(i int) int { return i + y*n })
+	}
+	for x := 0; x < n; x++ {
+		dft(func(i int) int { return x + i*n })
+	}
+}
@@ -0,0 +1,62 @@
+package simplex
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// spectralSlope returns the slope of log power against log frequency
+// for the grid, with the power averaged over rings of equal frequency
+func spectralSlope(g *Grid2D) float64 {
+	n := g.Width
+	re := append([]float64(nil), g.Data...)
+	im := make([]float64, len(re))
+	dft2(re, im, n, false)
+
+	power := make([]float64, n/2+1)
+	count := make([]int, n/2+1)
+	for v := 0; v < n; v++ {
+		for u := 0; u < n; u++ {
+			f := int(math.Round(math.Hypot(float64(signedFreq(u, n)), float64(signedFreq(v, n)))))
+			if f < 1 || f > n/2 {
+				continue
+			}
+			power[f] += re[u+v*n]*re[u+v*n] + im[u+v*n]*im[u+v*n]
+			count[f]++
+		}
+	}
+
+	// least squares fit over the frequencies clear of the DC term and
+	// the corners
+	var sx, sy, sxx, sxy, k float64
+	for f := 2; f <= n/4; f++ {
+		x := math.Log(float64(f))
+		y := math.Log(power[f] / float64(count[f]))
+		sx += x
+		sy += y
+		sxx += x * x
+		sxy += x * y
+		k++
+	}
+	return (k*sxy - sx*sy) / (k*sxx - sx*sx)
+}
+
+func TestSpectralSynthesis2(t *testing.T) {
+	n := New(rand.New(rand.NewSource(101)))
+
+	for _, beta := range []float64{0, 1, 2, 3} {
+		g := n.SpectralSynthesis2(64, beta)
+		if g.Width != 64 || g.Height != 64 {
+			t.Fatalf("Got %dx%d grid, expected 64x64", g.Width, g.Height)
+		}
+		for _, a := range g.Data {
+			if a < -1 || a > 1 {
+				t.Fatalf("Got %.4f, expected value in [-1,1]", a)
+			}
+		}
+		if slope := spectralSlope(g); math.Abs(slope+beta) > 0.2 {
+			t.Errorf("Got spectral slope %.4f, expected %.4f", slope, -beta)
+		}
+	}
+}
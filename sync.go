@@ -0,0 +1,54 @@
+package simplex
+
+import (
+	"math/rand"
+	"sync"
+)
+
+// SyncSimplex wraps a Simplex for use from several goroutines.  The
+// noise functions never modify a Simplex, but Reset and AutoCalibrate2
+// do; through the wrapper they take the write lock, so they can run
+// while other goroutines are sampling noise.  Calling them on the
+// wrapped Simplex directly bypasses the lock.
+type SyncSimplex struct {
+	mu sync.RWMutex
+	s  *Simplex
+}
+
+func NewSyncSimplex(s *Simplex) *SyncSimplex {
+	return &SyncSimplex{s: s}
+}
+
+func (ss *SyncSimplex) Noise2(x, y float64) float64 {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+	return ss.s.Noise2(x, y)
+}
+
+func (ss *SyncSimplex) Noise3(x, y, z float64) float64 {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+	return ss.s.Noise3(x, y, z)
+}
+
+func (ss *SyncSimplex) Noise4(x, y, z, w float64) float64 {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+	return ss.s.Noise4(x, y, z, w)
+}
+
+// Reset re-randomizes the wrapped Simplex (see Simplex.Reset) while
+// holding the write lock
+func (ss *SyncSimplex) Reset(r *rand.Rand) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	ss.s.Reset(r)
+}
+
+// AutoCalibrate2 calibrates the wrapped Simplex (see
+// Simplex.AutoCalibrate2) while holding the write lock
+func (ss *SyncSimplex) AutoCalibrate2(samples int, r *rand.Rand) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	ss.s.AutoCalibrate2(samples, r)
+}
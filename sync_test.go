@@ -0,0 +1,69 @@
+package simplex
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+)
+
+// run with -race to check for data races
+func TestSyncSimplex(t *testing.T) {
+	n := New(rand.New(rand.NewSource(101)))
+	ss := NewSyncSimplex(n)
+
+	var wg sync.WaitGroup
+	errs := make(chan string, 10)
+	for g := 0; g < 10; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				x := float64(g) + float64(i)*0.01
+				if ss.Noise2(x, 0.5) != n.Noise2(x, 0.5) ||
+					ss.Noise3(x, 0.5, 1.5) != n.Noise3(x, 0.5, 1.5) ||
+					ss.Noise4(x, 0.5, 1.5, 2.5) != n.Noise4(x, 0.5, 1.5, 2.5) {
+					errs <- "wrapped noise differs from the plain noise"
+					return
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	close(errs)
+	for e := range errs {
+		t.Error(e)
+	}
+}
+
+// run with -race to check for data races
+func TestSyncSimplexReset(t *testing.T) {
+	ss := NewSyncSimplex(New(rand.New(rand.NewSource(101))))
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				x := float64(g) + float64(i)*0.01
+				ss.Noise2(x, 0.5)
+				ss.Noise3(x, 0.5, 1.5)
+				ss.Noise4(x, 0.5, 1.5, 2.5)
+			}
+		}(g)
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 20; i++ {
+			ss.AutoCalibrate2(100, rand.New(rand.NewSource(int64(i))))
+			ss.Reset(rand.New(rand.NewSource(int64(i))))
+		}
+	}()
+	wg.Wait()
+
+	ss.Reset(rand.New(rand.NewSource(102)))
+	if a, b := ss.Noise2(0, 1.25), New(rand.New(rand.NewSource(102))).Noise2(0, 1.25); a != b {
+		t.Errorf("Got %.4f after Reset, expected %.4f", a, b)
+	}
+}
@@ -0,0 +1,200 @@
+package simplex
+
+import (
+	"math"
+)
+
+// FurPattern2 returns a fur-spot indicator at (x,y) from a simplified
+// Gray-Scott reaction-diffusion model.  A small patch of cells around
+// the point, spaced spotSize/4 apart, is seeded with the V chemical
+// wherever Noise2 is positive and then evolved for the given number of
+// iterations.  The U concentration at the center, in [0,1], is
+// returned; low values mark a spot.
+func FurPattern2(s *Simplex, x, y float64, iterations int, spotSize float64) float64 {
+	const (
+		n  = 9 // patch is n x n cells
+		du = 0.16
+		dv = 0.08
+		f  = 0.035
+		k  = 0.065
+	)
+	h := spotSize / 4
+	var u, v, nu, nv [n][n]float64
+	for j := 0; j < n; j++ {
+		for i := 0; i < n; i++ {
+			a := s.Noise2(x+float64(i-n/2)*h, y+float64(j-n/2)*h)
+			u[j][i] = 1
+			if a > 0 {
+				u[j][i] = 1 - a/2
+				v[j][i] = a / 2
+			}
+		}
+	}
+
+	clamp := func(i int) int {
+		if i < 0 {
+			return 0
+		}
+		if i >= n {
+			return n - 1
+		}
+		return i
+	}
+
+	for it := 0; it < iterations; it++ {
+		for j := 0; j < n; j++ {
+			for i := 0; i < n; i++ {
+				lu := u[j][clamp(i-1)] + u[j][clamp(i+1)] + u[clamp(j-1)][i] + u[clamp(j+1)][i] - 4*u[j][i]
+				lv := v[j][clamp(i-1)] + v[j][clamp(i+1)] + v[clamp(j-1)][i] + v[clamp(j+1)][i] - 4*v[j][i]
+				uvv := u[j][i] * v[j][i] * v[j][i]
+				nu[j][i] = u[j][i] + du*lu - uvv + f*(1-u[j][i])
+				nv[j][i] = v[j][i] + dv*lv + uvv - (f+k)*v[j][i]
+			}
+		}
+		u, v = nu, nv
+	}
+
+	c := u[n/2][n/2]
+	if c < 0 {
+		return 0
+	}
+	if c > 1 {
+		return 1
+	}
+	return c
+}
+
+// SeamlessTile2 makes a tileSize x tileSize tile (indexed [y][x]) of
+// Noise2, sampled at 1/16 noise units per pixel, that tiles by simple
+// repetition.  The interior is plain noise; within blendWidth pixels of
+// the right and bottom edges the noise is smoothstep-blended toward
+// the noise one tile to the left and above, so that the last pixel of
+// each row runs straight into the first one.
+func SeamlessTile2(s *Simplex, tileSize int, blendWidth int) [][]float64 {
+	if tileSize < 0 {
+		tileSize = 0
+	}
+	const scale = 1.0 / 16
+	size := float64(tileSize)
+	weight := func(i int) float64 {
+		t := float64(i-(tileSize-blendWidth)+1) / float64(blendWidth)
+		if blendWidth <= 0 || t <= 0 {
+			return 0
+		}
+		return smoothstep(t)
+	}
+	tile := make([][]float64, tileSize)
+	for y := 0; y < tileSize; y++ {
+		tile[y] = make([]float64, tileSize)
+		wy := weight(y)
+		fy := float64(y)
+		for x := 0; x < tileSize; x++ {
+			wx := weight(x)
+			fx := float64(x)
+			tile[y][x] = (1-wx)*(1-wy)*s.Noise2(fx*scale, fy*scale) +
+				wx*(1-wy)*s.Noise2((fx-size)*scale, fy*scale) +
+				(1-wx)*wy*s.Noise2(fx*scale, (fy-size)*scale) +
+				wx*wy*s.Noise2((fx-size)*scale, (fy-size)*scale)
+		}
+	}
+	return tile
+}
+
+// smoothstep is the cubic 3t^2-2t^3, clamped to [0,1]
+func smoothstep(t float64) float64 {
+	if t <= 0 {
+		return 0
+	}
+	if t >= 1 {
+		return 1
+	}
+	return t * t * (3 - 2*t)
+}
+
+// LavaCracks2 returns the glow of a lava crack pattern at (x, y), in
+// [0,1].  The cracks run along the zero crossings of FBM2 (sampled at
+// crackFrequency), where the ridged noise |FBM2| is near zero; the
+// glow is 1 right on a crack and fades out over glowWidth.
+func LavaCracks2(s *Simplex, x, y float64, crackFrequency, glowWidth float64) float64 {
+	ridge := math.Abs(s.FBM2(x*crackFrequency, y*crackFrequency, 3, 2, 0.5))
+	if glowWidth <= 0 {
+		return 0
+	}
+	return 1 - smoothstep(ridge/glowWidth)
+}
+
+// WoodTexture2 returns the grain of a log cut across at (x, y), in
+// [-1,1]: concentric rings around the origin, rings per unit of radius,
+// pushed around by Turbulence2
+func (s *Simplex) WoodTexture2(x, y, rings, turbStrength float64, turbOctaves int) float64 {
+	r := math.Sqrt(x*x+y*y) + turbStrength*s.Turbulence2(x, y, turbOctaves, 2, 0.5)
+	return math.Sin(rings * r)
+}
+
+// MarbleTexture3 returns a marble pattern at (x, y, z), in [-1,1]:
+// veins running across y, veins per unit of y, distorted by
+// Turbulence3
+func (s *Simplex) MarbleTexture3(x, y, z, veins, turbStrength float64, turbOctaves int) float64 {
+	return math.Sin(veins*y + turbStrength*s.Turbulence3(x, y, z, turbOctaves, 2, 0.5))
+}
+
+// CloudTexture2 returns cloud cover at (x, y), in [0,1].  FBM2 is cut
+// off below 0.1 (clear sky) and the rest is stretched back to [0,1].
+func (s *Simplex) CloudTexture2(x, y float64, octaves int) float64 {
+	return math.Max(0, s.FBM2(x, y, octaves, 2, 0.5)-0.1) / 0.9
+}
+
+// SeamlessNoise2 returns noise at (x, y) that repeats every sx along x
+// and every sy along y.  The two axes are wrapped around circles (the
+// x and y circles together make a torus in 4D), with radii chosen so
+// the features come out about the same size as those of Noise2.
+func (s *Simplex) SeamlessNoise2(x, y, sx, sy float64) float64 {
+	ax := 2 * math.Pi * x / sx
+	ay := 2 * math.Pi * y / sy
+	rx := sx / (2 * math.Pi)
+	ry := sy / (2 * math.Pi)
+	return s.Noise4(rx*math.Cos(ax), rx*math.Sin(ax), ry*math.Cos(ay), ry*math.Sin(ay))
+}
+
+// SeamlessOctaveNoise2 sums octaves of SeamlessNoise2 the way FBM2 sums
+// octaves of Noise2.  Each octave is scaled up along with its period, so
+// they all repeat every sx along x and every sy along y.
+func (s *Simplex) SeamlessOctaveNoise2(x, y, sx, sy float64, octaves int, lacunarity, persistence float64) float64 {
+	var sum, total float64
+	f := 1.0
+	a := 1.0
+	for i := 0; i < octaves; i++ {
+		sum += a * s.SeamlessNoise2(x*f, y*f, sx*f, sy*f)
+		total += a
+		f *= lacunarity
+		a *= persistence
+	}
+	if total == 0 {
+		return 0
+	}
+	return sum / total
+}
+
+// StripePattern2 returns stripes at (x, y), in [-1,1]: a sine wave
+// running along x with frequency stripes per unit, whose phase is
+// pushed around by perturbStrength times Noise2
+func (s *Simplex) StripePattern2(x, y, frequency, perturbStrength float64) float64 {
+	return math.Sin(2 * math.Pi * frequency * (x + perturbStrength*s.Noise2(x, y)))
+}
+
+// Checkerboard2 returns 1 or -1 for the square of side cellSize that
+// contains (x, y) after it is displaced by NoiseVec2, so the squares get
+// wobbly, organic edges.  The noise is sampled in units of cellSize, and
+// the displacement is up to perturbStrength cells in each direction.
+func (s *Simplex) Checkerboard2(x, y, cellSize, perturbStrength float64) float64 {
+	u, v := x/cellSize, y/cellSize
+	if perturbStrength != 0 {
+		d := s.NoiseVec2(u, v)
+		u += perturbStrength * d[0]
+		v += perturbStrength * d[1]
+	}
+	if (fastfloor(u)+fastfloor(v))&1 == 0 {
+		return 1
+	}
+	return -1
+}
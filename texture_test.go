@@ -0,0 +1,320 @@
+package simplex
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestFurPattern2(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	var spots int
+	for i := 0; i < 1000; i++ {
+		x := r.Float64() * 10
+		y := r.Float64() * 10
+		a := FurPattern2(n, x, y, 20, 1)
+		if a < 0 || a > 1 {
+			t.Fatalf("got %.4f, expected value in [0,1]", a)
+		}
+		if a < 0.9 {
+			spots++
+		}
+	}
+	if spots == 0 || spots == 1000 {
+		t.Errorf("got %d spot samples out of 1000, expected a mix", spots)
+	}
+
+	// with no iterations, U is just the initial condition
+	a := FurPattern2(n, 0.3, 1.25, 0, 1)
+	a0 := 1.0
+	if v := n.Noise2(0.3, 1.25); v > 0 {
+		a0 = 1 - v/2
+	}
+	if a != a0 {
+		t.Errorf("Got %.4f, expected %.4f", a, a0)
+	}
+}
+
+func TestSeamlessTile2(t *testing.T) {
+	n := New(rand.New(rand.NewSource(101)))
+
+	const size = 64
+	tile := SeamlessTile2(n, size, 16)
+	if len(tile) != size || len(tile[0]) != size {
+		t.Fatalf("got %dx%d tile, expected %dx%d", len(tile[0]), len(tile), size, size)
+	}
+
+	// the jump across a seam should be no worse than the jumps
+	// between neighbors inside the tile
+	var interior, seam float64
+	for y := 0; y < size; y++ {
+		for x := 0; x < size-1; x++ {
+			interior = math.Max(interior, math.Abs(tile[y][x+1]-tile[y][x]))
+			interior = math.Max(interior, math.Abs(tile[x+1][y]-tile[x][y]))
+		}
+		seam = math.Max(seam, math.Abs(tile[y][0]-tile[y][size-1]))
+		seam = math.Max(seam, math.Abs(tile[0][y]-tile[size-1][y]))
+	}
+	if seam > interior {
+		t.Errorf("got jump of %.4f across the seam, but at most %.4f inside", seam, interior)
+	}
+
+	// away from the blend zone, the tile is plain noise
+	a := tile[10][20]
+	a0 := n.Noise2(20.0/16, 10.0/16)
+	if a != a0 {
+		t.Errorf("Got %.4f, expected %.4f", a, a0)
+	}
+
+	for _, size := range []int{0, -1, -64} {
+		if tile := SeamlessTile2(n, size, 16); len(tile) != 0 {
+			t.Errorf("got %d rows for size %d, expected none", len(tile), size)
+		}
+	}
+}
+
+func TestLavaCracks2(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	var bright int
+	for i := 0; i < 100000; i++ {
+		x := r.Float64() * 100
+		y := r.Float64() * 100
+		a := LavaCracks2(n, x, y, 0.5, 0.05)
+		if a < 0 || a > 1 {
+			t.Fatalf("got %.4f, expected value in [0,1]", a)
+		}
+		if a > 0.5 {
+			bright++
+		}
+	}
+	// cracks are thin
+	if bright == 0 || bright > 20000 {
+		t.Errorf("got %d bright samples out of 100000, expected a thin network of cracks", bright)
+	}
+
+	// right on a zero crossing, the crack is at full brightness
+	x0, x1 := 0.0, 0.0
+	for x := 0.0; x < 100; x += 0.01 {
+		if n.FBM2(x*0.5, 0, 3, 2, 0.5) < 0 && n.FBM2((x+0.01)*0.5, 0, 3, 2, 0.5) >= 0 {
+			x0, x1 = x, x+0.01
+			break
+		}
+	}
+	for i := 0; i < 50; i++ {
+		m := (x0 + x1) / 2
+		if n.FBM2(m*0.5, 0, 3, 2, 0.5) < 0 {
+			x0 = m
+		} else {
+			x1 = m
+		}
+	}
+	if a := LavaCracks2(n, x0, 0, 0.5, 0.05); a < 0.999 {
+		t.Errorf("got %.4f on a crack, expected 1", a)
+	}
+}
+
+func TestWoodTexture2(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	for i := 0; i < 100000; i++ {
+		x := r.Float64()*20 - 10
+		y := r.Float64()*20 - 10
+		if a := n.WoodTexture2(x, y, 10, 0.3, 4); a < -1 || a > 1 {
+			t.Fatalf("got %.4f at (%g, %g), expected value in [-1,1]", a, x, y)
+		}
+	}
+
+	// without turbulence, the rings are perfect circles: going out
+	// along any radius we cross zero at every multiple of pi/rings
+	const rings = 10
+	for _, th := range []float64{0, 0.7, 2, 4.5} {
+		crossings := 0
+		prev := n.WoodTexture2(0.001*math.Cos(th), 0.001*math.Sin(th), rings, 0, 4)
+		for d := 0.001; d < 3; d += 0.001 {
+			a := n.WoodTexture2(d*math.Cos(th), d*math.Sin(th), rings, 0, 4)
+			if (a < 0) != (prev < 0) {
+				crossings++
+			}
+			prev = a
+		}
+		if expect := int(math.Floor(3 * rings / math.Pi)); crossings != expect {
+			t.Errorf("got %d ring crossings at angle %g, expected %d", crossings, th, expect)
+		}
+	}
+}
+
+func TestMarbleTexture3(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	// small steps along any axis give small changes
+	const e = 1e-6
+	for i := 0; i < 10000; i++ {
+		x := r.Float64() * 10
+		y := r.Float64() * 10
+		z := r.Float64() * 10
+		a := n.MarbleTexture3(x, y, z, 5, 2, 4)
+		for _, b := range []float64{
+			n.MarbleTexture3(x+e, y, z, 5, 2, 4),
+			n.MarbleTexture3(x, y+e, z, 5, 2, 4),
+			n.MarbleTexture3(x, y, z+e, 5, 2, 4),
+		} {
+			if math.Abs(a-b) > 1000*e {
+				t.Fatalf("got a jump from %.6f to %.6f near (%g, %g, %g)", a, b, x, y, z)
+			}
+		}
+	}
+
+	// in the z=0 slice, the veins cross y over and over, and the
+	// turbulence bends them so they are not straight lines in x
+	crossings := 0
+	prev := n.MarbleTexture3(1.5, 0, 0, 5, 2, 4)
+	for y := 0.01; y < 10; y += 0.01 {
+		a := n.MarbleTexture3(1.5, y, 0, 5, 2, 4)
+		if (a < 0) != (prev < 0) {
+			crossings++
+		}
+		prev = a
+	}
+	if crossings < 10 {
+		t.Errorf("got %d veins along y, expected a visible vein pattern", crossings)
+	}
+	var spread float64
+	for x := 0.0; x < 10; x += 0.1 {
+		spread = math.Max(spread, math.Abs(n.MarbleTexture3(x, 2, 0, 5, 2, 4)-n.MarbleTexture3(0, 2, 0, 5, 2, 4)))
+	}
+	if spread < 0.1 {
+		t.Errorf("got spread %.4f across x, expected the veins to wander", spread)
+	}
+}
+
+func TestCloudTexture2(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	var sum float64
+	clear := 0
+	for i := 0; i < 1000000; i++ {
+		x := r.Float64() * 1000
+		y := r.Float64() * 1000
+		a := n.CloudTexture2(x, y, 5)
+		if a < 0 || a > 1 {
+			t.Fatalf("got %.4f at (%g, %g), expected value in [0,1]", a, x, y)
+		}
+		if a == 0 {
+			clear++
+		}
+		sum += a
+	}
+	if mean := sum / 1000000; mean > 0.2 {
+		t.Errorf("got mean cover %.4f, expected well below 0.5", mean)
+	}
+	if clear < 500000 {
+		t.Errorf("got %d clear samples out of 1000000, expected most of the sky clear", clear)
+	}
+}
+
+func TestSeamlessOctaveNoise2(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	const sx, sy = 7.5, 4
+	for octaves := 1; octaves <= 8; octaves++ {
+		for i := 0; i < 100; i++ {
+			x := r.Float64() * sx
+			y := r.Float64() * sy
+			a := n.SeamlessOctaveNoise2(x, y, sx, sy, octaves, 2, 0.5)
+			if a < -1 || a > 1 {
+				t.Fatalf("got %.4f at (%g, %g), expected value in [-1,1]", a, x, y)
+			}
+			if b := n.SeamlessOctaveNoise2(x+sx, y, sx, sy, octaves, 2, 0.5); math.Abs(a-b) > 1e-9 {
+				t.Fatalf("%d octaves: got %.6f and %.6f one period apart in x", octaves, a, b)
+			}
+			if b := n.SeamlessOctaveNoise2(x, y-sy, sx, sy, octaves, 2, 0.5); math.Abs(a-b) > 1e-9 {
+				t.Fatalf("%d octaves: got %.6f and %.6f one period apart in y", octaves, a, b)
+			}
+		}
+		a := n.SeamlessOctaveNoise2(0, 1.5, sx, sy, octaves, 1.7, 0.5)
+		b := n.SeamlessOctaveNoise2(sx, 1.5, sx, sy, octaves, 1.7, 0.5)
+		if math.Abs(a-b) > 1e-9 {
+			t.Errorf("%d octaves: got %.6f at x=0 and %.6f at x=%g", octaves, a, b, sx)
+		}
+	}
+
+	// one octave is just SeamlessNoise2, which is not constant
+	if a, b := n.SeamlessOctaveNoise2(1, 2, sx, sy, 1, 2, 0.5), n.SeamlessNoise2(1, 2, sx, sy); a != b {
+		t.Errorf("got %.4f, expected %.4f", a, b)
+	}
+	if a, b := n.SeamlessNoise2(1, 2, sx, sy), n.SeamlessNoise2(2, 2, sx, sy); a == b {
+		t.Errorf("got %.4f at two different points", a)
+	}
+}
+
+func TestStripePattern2(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	// irregularity is the mean distance from the unperturbed stripes
+	irregularity := func(strength float64) float64 {
+		var sum float64
+		for i := 0; i < 10000; i++ {
+			x := r.Float64() * 20
+			y := r.Float64() * 20
+			a := n.StripePattern2(x, y, 1.5, strength)
+			if a < -1 || a > 1 {
+				t.Fatalf("Got %.4f at (%g,%g), expected value in [-1,1]", a, x, y)
+			}
+			if strength == 0 {
+				if b := math.Sin(2 * math.Pi * 1.5 * x); a != b {
+					t.Fatalf("Got %.4f at (%g,%g), expected %.4f", a, x, y, b)
+				}
+			}
+			sum += math.Abs(a - math.Sin(2*math.Pi*1.5*x))
+		}
+		return sum / 10000
+	}
+	if a := irregularity(0); a != 0 {
+		t.Errorf("Got irregularity %.4f with no perturbation, expected 0", a)
+	}
+	if low, high := irregularity(0.05), irregularity(0.3); high < 2*low {
+		t.Errorf("Got irregularity %.4f with strength 0.3, expected well above %.4f with 0.05", high, low)
+	}
+}
+
+func TestCheckerboard2(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	// changed is the fraction of points that land in a different
+	// square from the hard checkerboard
+	changed := func(strength float64) float64 {
+		var count int
+		for i := 0; i < 10000; i++ {
+			x := r.Float64()*40 - 20
+			y := r.Float64()*40 - 20
+			a := n.Checkerboard2(x, y, 2.5, strength)
+			if a != 1 && a != -1 {
+				t.Fatalf("Got %.4f at (%g,%g), expected 1 or -1", a, x, y)
+			}
+			hard := 1.0
+			if (int(math.Floor(x/2.5))+int(math.Floor(y/2.5)))%2 != 0 {
+				hard = -1
+			}
+			if a != hard {
+				count++
+			}
+		}
+		return float64(count) / 10000
+	}
+	if a := changed(0); a != 0 {
+		t.Errorf("Got %.4f of points changed with no perturbation, expected 0", a)
+	}
+	if low, high := changed(0.05), changed(0.3); low == 0 || high < 2*low {
+		t.Errorf("Got %.4f of points changed with strength 0.3, expected well above %.4f with 0.05", high, low)
+	}
+}
@@ -0,0 +1,50 @@
+package simplex
+
+import (
+	"math"
+	"math/rand"
+)
+
+// NetworkTopology2 builds a random network of the given number of
+// nodes, for exercising distributed systems code.  Nodes are scattered
+// over a 10 x 10 square by rejection sampling against Noise2, so they
+// cluster where the noise is high.  Each pair of nodes is then linked
+// with probability exp(-d*connectionProbabilityDecay), where d is the
+// distance between them.  The seed drives both the placement and the
+// linking, so the same arguments always give the same network.  The
+// adjacency matrix is symmetric with no self links, and empty if there
+// are no nodes.
+func NetworkTopology2(s *Simplex, nodes int, connectionProbabilityDecay float64, seed int64) (adjacency [][]bool) {
+	if nodes <= 0 {
+		return [][]bool{}
+	}
+	r := rand.New(rand.NewSource(seed))
+
+	pos := make([][2]float64, nodes)
+	for i := range pos {
+		for {
+			x := r.Float64() * 10
+			y := r.Float64() * 10
+			w := (s.Noise2(x, y) + 1) / 2
+			if r.Float64() < w*w {
+				pos[i] = [2]float64{x, y}
+				break
+			}
+		}
+	}
+
+	adjacency = make([][]bool, nodes)
+	for i := range adjacency {
+		adjacency[i] = make([]bool, nodes)
+	}
+	for i := 0; i < nodes; i++ {
+		for j := i + 1; j < nodes; j++ {
+			d := math.Hypot(pos[i][0]-pos[j][0], pos[i][1]-pos[j][1])
+			if r.Float64() < math.Exp(-d*connectionProbabilityDecay) {
+				adjacency[i][j] = true
+				adjacency[j][i] = true
+			}
+		}
+	}
+	return adjacency
+}
@@ -0,0 +1,54 @@
+package simplex
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestNetworkTopology2(t *testing.T) {
+	n := New(rand.New(rand.NewSource(101)))
+
+	edges := func(adj [][]bool) int {
+		k := 0
+		for i := range adj {
+			for j := range adj[i] {
+				if adj[i][j] {
+					k++
+				}
+			}
+		}
+		return k / 2
+	}
+
+	a := NetworkTopology2(n, 50, 0.5, 7)
+	if len(a) != 50 || len(a[0]) != 50 {
+		t.Fatalf("got %dx%d adjacency, expected 50x50", len(a), len(a[0]))
+	}
+	for i := range a {
+		if a[i][i] {
+			t.Errorf("node %d is linked to itself", i)
+		}
+		for j := range a {
+			if a[i][j] != a[j][i] {
+				t.Fatalf("link %d-%d is not symmetric", i, j)
+			}
+		}
+	}
+
+	if !reflect.DeepEqual(a, NetworkTopology2(n, 50, 0.5, 7)) {
+		t.Errorf("same seed produced a different network")
+	}
+
+	sparse := edges(NetworkTopology2(n, 50, 5, 7))
+	dense := edges(a)
+	if sparse >= dense {
+		t.Errorf("got %d links with fast decay, expected fewer than %d", sparse, dense)
+	}
+
+	for _, nodes := range []int{0, -1, -50} {
+		if a := NetworkTopology2(n, nodes, 0.5, 7); a == nil || len(a) != 0 {
+			t.Errorf("got %d rows for %d nodes, expected an empty matrix", len(a), nodes)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package simplex
+
+import (
+	"math"
+)
+
+// value noise uses the same permutation table as the simplex noise,
+// but hashes each lattice corner to a value rather than a gradient
+
+// latticeValue maps a permutation entry to a value in [-1,1]
+func latticeValue(p int) float64 {
+	return float64(p)/127.5 - 1
+}
+
+// quintic is Perlin's improved fade curve, 6t^5-15t^4+10t^3
+func quintic(t float64) float64 {
+	return t * t * t * (t*(t*6-15) + 10)
+}
+
+// ValueNoise2 returns 2D value noise at (x, y), in [-1,1].  Each
+// lattice point gets a pseudo-random value and the result is
+// interpolated between the four surrounding points using a quintic
+// fade.  Compared with Noise2, value noise puts more of its energy at
+// low frequencies and looks blobbier.
+func (s *Simplex) ValueNoise2(x, y float64) float64 {
+	i := fastfloor(x)
+	j := fastfloor(y)
+	u := quintic(x - float64(i))
+	v := quintic(y - float64(j))
+
+	v00 := latticeValue(s.getPerm(i + s.getPerm(j)))
+	v10 := latticeValue(s.getPerm(i + 1 + s.getPerm(j)))
+	v01 := latticeValue(s.getPerm(i + s.getPerm(j+1)))
+	v11 := latticeValue(s.getPerm(i + 1 + s.getPerm(j+1)))
+
+	return lerp(lerp(v00, v10, u), lerp(v01, v11, u), v)
+}
+
+// SmoothValueNoise2 is another name for ValueNoise2, for code that
+// wants to say it relies on the quintic fade.  With the cubic Hermite
+// 3t^2-2t^3 the slope is continuous across the lattice lines but the
+// curvature jumps, which shows up as faint creases (in shading, say);
+// both derivatives of the quintic vanish at the ends, so there are
+// none.
+func (s *Simplex) SmoothValueNoise2(x, y float64) float64 {
+	return s.ValueNoise2(x, y)
+}
+
+// ValueNoise3 is the 3D version of ValueNoise2
+func (s *Simplex) ValueNoise3(x, y, z float64) float64 {
+	i := fastfloor(x)
+	j := fastfloor(y)
+	k := fastfloor(z)
+	u := quintic(x - float64(i))
+	v := quintic(y - float64(j))
+	w := quintic(z - float64(k))
+
+	p := func(di, dj, dk int) float64 {
+		return latticeValue(s.getPerm(i + di + s.getPerm(j+dj+s.getPerm(k+dk))))
+	}
+
+	return lerp(
+		lerp(lerp(p(0, 0, 0), p(1, 0, 0), u), lerp(p(0, 1, 0), p(1, 1, 0), u), v),
+		lerp(lerp(p(0, 0, 1), p(1, 0, 1), u), lerp(p(0, 1, 1), p(1, 1, 1), u), v),
+		w)
+}
+
+// ValueNoise4 is the 4D version of ValueNoise2
+func (s *Simplex) ValueNoise4(x, y, z, w float64) float64 {
+	i := fastfloor(x)
+	j := fastfloor(y)
+	k := fastfloor(z)
+	l := fastfloor(w)
+	fx := quintic(x - float64(i))
+	fy := quintic(y - float64(j))
+	fz := quintic(z - float64(k))
+	fw := quintic(w - float64(l))
+
+	p := func(di, dj, dk, dl int) float64 {
+		return latticeValue(s.getPerm(i + di + s.getPerm(j+dj+s.getPerm(k+dk+s.getPerm(l+dl)))))
+	}
+	cube := func(dl int) float64 {
+		return lerp(
+			lerp(lerp(p(0, 0, 0, dl), p(1, 0, 0, dl), fx), lerp(p(0, 1, 0, dl), p(1, 1, 0, dl), fx), fy),
+			lerp(lerp(p(0, 0, 1, dl), p(1, 0, 1, dl), fx), lerp(p(0, 1, 1, dl), p(1, 1, 1, dl), fx), fy),
+			fz)
+	}
+
+	return lerp(cube(0), cube(1), fw)
+}
+
+// WhiteNoise2 returns uncorrelated noise: (x, y) is snapped to the
+// nearest lattice point, whose pseudo-random value in [-1,1] is
+// returned with no interpolation at all.
+func (s *Simplex) WhiteNoise2(x, y float64) float64 {
+	return s.IntegerCoordNoise2(int(math.Floor(x+0.5)), int(math.Floor(y+0.5)))
+}
+
+// IntegerCoordNoise2 returns the pseudo-random value in [-1,1] of the
+// lattice point (ix, iy), straight from the permutation table.  The
+// values repeat every 256 points along each axis, and each of the 256
+// values comes up equally often in every 256x256 block.
+func (s *Simplex) IntegerCoordNoise2(ix, iy int) float64 {
+	return latticeValue(s.getPerm(ix + s.getPerm(iy)))
+}
@@ -0,0 +1,264 @@
+package simplex
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestValueNoise2(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	for i := 0; i < 1000000; i++ {
+		x := r.Float64() * 100
+		y := r.Float64() * 100
+		a := n.ValueNoise2(x, y)
+		if a < -1 || a > 1 {
+			t.Fatalf("got %.4f at (%g, %g), expected value in [-1,1]", a, x, y)
+		}
+	}
+
+	// value noise stays correlated over longer distances, i.e., it
+	// has more energy at low frequencies
+	lv := correlationLength(n.ValueNoise2, 1, 0)
+	ls := correlationLength(n.Noise2, 1, 0)
+	if lv <= ls*1.2 {
+		t.Errorf("got correlation length %.3f for value noise and %.3f for simplex noise, expected value noise to be longer", lv, ls)
+	}
+}
+
+func BenchmarkValueNoise2(b *testing.B) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	x := 0.001
+	y := 0.0001
+	for i := 0; i < b.N; i++ {
+		n.ValueNoise2(x, y)
+		x += 0.00000011
+		y += 0.00000012
+	}
+}
+
+func TestValueNoise3(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	for i := 0; i < 1000000; i++ {
+		x := r.Float64() * 100
+		y := r.Float64() * 100
+		z := r.Float64() * 100
+		a := n.ValueNoise3(x, y, z)
+		if a < -1 || a > 1 {
+			t.Fatalf("got %.4f at (%g, %g, %g), expected value in [-1,1]", a, x, y, z)
+		}
+	}
+
+	lv := correlationLength(func(x, y float64) float64 {
+		return n.ValueNoise3(x, y, 0.37)
+	}, 1, 0)
+	ls := correlationLength(func(x, y float64) float64 {
+		return n.Noise3(x, y, 0.37)
+	}, 1, 0)
+	if lv <= ls*1.2 {
+		t.Errorf("got correlation length %.3f for value noise and %.3f for simplex noise, expected value noise to be longer", lv, ls)
+	}
+}
+
+func TestValueNoise4(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	for i := 0; i < 1000000; i++ {
+		x := r.Float64() * 100
+		y := r.Float64() * 100
+		z := r.Float64() * 100
+		w := r.Float64() * 100
+		a := n.ValueNoise4(x, y, z, w)
+		if a < -1 || a > 1 {
+			t.Fatalf("got %.4f at (%g, %g, %g, %g), expected value in [-1,1]", a, x, y, z, w)
+		}
+	}
+
+	lv := correlationLength(func(x, y float64) float64 {
+		return n.ValueNoise4(x, y, 0.37, 0.71)
+	}, 1, 0)
+	ls := correlationLength(func(x, y float64) float64 {
+		return n.Noise4(x, y, 0.37, 0.71)
+	}, 1, 0)
+	if lv <= ls*1.2 {
+		t.Errorf("got correlation length %.3f for value noise and %.3f for simplex noise, expected value noise to be longer", lv, ls)
+	}
+}
+
+func BenchmarkValueNoise3(b *testing.B) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	x := 0.001
+	y := 0.0001
+	z := 0.00001
+	for i := 0; i < b.N; i++ {
+		n.ValueNoise3(x, y, z)
+		x += 0.00000011
+		y += 0.00000012
+		z += 0.00000013
+	}
+}
+
+func BenchmarkValueNoise4(b *testing.B) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	x := 0.001
+	y := 0.0001
+	z := 0.00001
+	w := 0.000001
+	for i := 0; i < b.N; i++ {
+		n.ValueNoise4(x, y, z, w)
+		x += 0.00000011
+		y += 0.00000012
+		z += 0.00000013
+		w += 0.00000014
+	}
+}
+
+// cubicValueNoise2 is ValueNoise2 with the cubic Hermite fade in place
+// of the quintic, for comparison
+func cubicValueNoise2(s *Simplex, x, y float64) float64 {
+	i := fastfloor(x)
+	j := fastfloor(y)
+	u := smoothstep(x - float64(i))
+	v := smoothstep(y - float64(j))
+
+	v00 := latticeValue(s.getPerm(i + s.getPerm(j)))
+	v10 := latticeValue(s.getPerm(i + 1 + s.getPerm(j)))
+	v01 := latticeValue(s.getPerm(i + s.getPerm(j+1)))
+	v11 := latticeValue(s.getPerm(i + 1 + s.getPerm(j+1)))
+
+	return lerp(lerp(v00, v10, u), lerp(v01, v11, u), v)
+}
+
+func TestSmoothValueNoise2(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	// compare one-sided finite differences on either side of the
+	// lattice lines
+	const h = 1e-4
+	var cubicJumps int
+	for i := 0; i < 1000; i++ {
+		k := float64(r.Intn(200) - 100)
+		y := r.Float64() * 100
+		if a, b := n.SmoothValueNoise2(k+0.3, y), n.ValueNoise2(k+0.3, y); a != b {
+			t.Fatalf("Got %.4f, expected the same as ValueNoise2 %.4f", a, b)
+		}
+		slopes := func(f func(x float64) float64) (left, right, left2, right2 float64) {
+			left = (f(k) - f(k-h)) / h
+			right = (f(k+h) - f(k)) / h
+			left2 = (f(k) - 2*f(k-h) + f(k-2*h)) / (h * h)
+			right2 = (f(k+2*h) - 2*f(k+h) + f(k)) / (h * h)
+			return
+		}
+
+		left, right, left2, right2 := slopes(func(x float64) float64 { return n.SmoothValueNoise2(x, y) })
+		if math.Abs(left-right) > 1e-3 {
+			t.Fatalf("got slopes %.6f and %.6f either side of x=%g", left, right, k)
+		}
+		if math.Abs(left2-right2) > 0.1 {
+			t.Fatalf("got curvatures %.6f and %.6f either side of x=%g", left2, right2, k)
+		}
+
+		// the cubic fade keeps the slope continuous, but not the
+		// curvature
+		left, right, left2, right2 = slopes(func(x float64) float64 { return cubicValueNoise2(n, x, y) })
+		if math.Abs(left-right) > 1e-3 {
+			t.Fatalf("got cubic slopes %.6f and %.6f either side of x=%g", left, right, k)
+		}
+		if math.Abs(left2-right2) > 0.5 {
+			cubicJumps++
+		}
+
+		left, right, _, _ = slopes(func(y float64) float64 { return n.SmoothValueNoise2(k+0.5, y) })
+		if math.Abs(left-right) > 1e-3 {
+			t.Fatalf("got slopes %.6f and %.6f either side of y=%g", left, right, k)
+		}
+	}
+	if cubicJumps < 500 {
+		t.Errorf("got %d curvature jumps out of 1000 with the cubic fade, expected most of them", cubicJumps)
+	}
+}
+
+func TestWhiteNoise2(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	var a, b []float64
+	var hist [10]int
+	for i := 0; i < 10000; i++ {
+		x := float64(r.Intn(2000) - 1000)
+		y := float64(r.Intn(2000) - 1000)
+		v := n.WhiteNoise2(x, y)
+		if v < -1 || v > 1 {
+			t.Fatalf("got %.4f, expected value in [-1,1]", v)
+		}
+		if w := n.WhiteNoise2(x+0.3, y-0.4); w != v {
+			t.Fatalf("got %.4f and %.4f within the same cell", v, w)
+		}
+		a = append(a, v)
+		b = append(b, n.WhiteNoise2(x+1, y))
+		k := int((v + 1) / 2 * 10)
+		if k == 10 {
+			k = 9
+		}
+		hist[k]++
+	}
+	if c := correlation(a, b); math.Abs(c) > 0.05 {
+		t.Errorf("got correlation %.4f between adjacent cells, expected about 0", c)
+	}
+	for k, count := range hist {
+		if count < 800 || count > 1200 {
+			t.Errorf("got %d samples in bin %d, expected about 1000", count, k)
+		}
+	}
+}
+
+func TestIntegerCoordNoise2(t *testing.T) {
+	n := New(rand.New(rand.NewSource(101)))
+
+	var v, right, down []float64
+	counts := make(map[float64]int)
+	for iy := 0; iy < 256; iy++ {
+		for ix := 0; ix < 256; ix++ {
+			a := n.IntegerCoordNoise2(ix, iy)
+			if a < -1 || a > 1 {
+				t.Fatalf("got %.4f, expected value in [-1,1]", a)
+			}
+			if b := n.IntegerCoordNoise2(ix, iy); b != a {
+				t.Fatalf("got %.4f and then %.4f at (%d,%d)", a, b, ix, iy)
+			}
+			if b := n.IntegerCoordNoise2(ix-256, iy+512); b != a {
+				t.Fatalf("got %.4f and %.4f, expected a period of 256", a, b)
+			}
+			v = append(v, a)
+			right = append(right, n.IntegerCoordNoise2(ix+1, iy))
+			down = append(down, n.IntegerCoordNoise2(ix, iy+1))
+			counts[a]++
+		}
+	}
+	if c := correlation(v, right); math.Abs(c) > 0.05 {
+		t.Errorf("got correlation %.4f between horizontally adjacent cells, expected about 0", c)
+	}
+	if c := correlation(v, down); math.Abs(c) > 0.05 {
+		t.Errorf("got correlation %.4f between vertically adjacent cells, expected about 0", c)
+	}
+	if len(counts) != 256 {
+		t.Errorf("got %d distinct values, expected 256", len(counts))
+	}
+	for a, count := range counts {
+		if count != 256 {
+			t.Errorf("got %.4f %d times, expected 256", a, count)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package simplex
+
+// offsets used to get independent channels out of one noise source.
+// They are several lattice cells apart and not simple multiples of
+// each other, so the channels don't share any structure.
+var vecOffsets = [...][2]float64{
+	{3.33, 5.71},
+	{7.09, 1.97},
+	{11.53, 9.41},
+	{2.87, 13.19},
+}
+
+// NoiseVec2 returns two independent Noise2 values at (x, y), sampled at
+// (x+3.33, y+5.71) and (x+7.09, y+1.97)
+func (s *Simplex) NoiseVec2(x, y float64) [2]float64 {
+	return [2]float64{
+		s.Noise2(x+vecOffsets[0][0], y+vecOffsets[0][1]),
+		s.Noise2(x+vecOffsets[1][0], y+vecOffsets[1][1]),
+	}
+}
+
+// NoiseVec3 adds a third channel to NoiseVec2, sampled at
+// (x+11.53, y+9.41)
+func (s *Simplex) NoiseVec3(x, y float64) [3]float64 {
+	var v [3]float64
+	for k := range v {
+		v[k] = s.Noise2(x+vecOffsets[k][0], y+vecOffsets[k][1])
+	}
+	return v
+}
+
+// NoiseVec4 adds a fourth channel to NoiseVec3, sampled at
+// (x+2.87, y+13.19)
+func (s *Simplex) NoiseVec4(x, y float64) [4]float64 {
+	var v [4]float64
+	for k := range v {
+		v[k] = s.Noise2(x+vecOffsets[k][0], y+vecOffsets[k][1])
+	}
+	return v
+}
+
+// RGBNoise2 returns the three channels of NoiseVec3 as color
+// components, each mapped to [0,1] by Normalize
+func (s *Simplex) RGBNoise2(x, y float64) (r, g, b float64) {
+	v := s.NoiseVec3(x, y)
+	return Normalize(v[0]), Normalize(v[1]), Normalize(v[2])
+}
@@ -0,0 +1,98 @@
+package simplex
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// channelCorrelations samples f at random points and returns the
+// correlation between every pair of channels
+func channelCorrelations(f func(x, y float64) []float64) []float64 {
+	r := rand.New(rand.NewSource(101))
+	var ch [][]float64
+	for i := 0; i < 10000; i++ {
+		v := f(r.Float64()*1000, r.Float64()*1000)
+		if ch == nil {
+			ch = make([][]float64, len(v))
+		}
+		for k := range v {
+			ch[k] = append(ch[k], v[k])
+		}
+	}
+	var c []float64
+	for i := range ch {
+		for j := i + 1; j < len(ch); j++ {
+			c = append(c, correlation(ch[i], ch[j]))
+		}
+	}
+	return c
+}
+
+func TestNoiseVec2(t *testing.T) {
+	n := New(rand.New(rand.NewSource(101)))
+
+	c := channelCorrelations(func(x, y float64) []float64 {
+		v := n.NoiseVec2(x, y)
+		return v[:]
+	})
+	if math.Abs(c[0]) > 0.05 {
+		t.Errorf("Got correlation %.4f, expected less than 0.05", c[0])
+	}
+}
+
+func TestNoiseVec3(t *testing.T) {
+	n := New(rand.New(rand.NewSource(101)))
+
+	c := channelCorrelations(func(x, y float64) []float64 {
+		v := n.NoiseVec3(x, y)
+		if v2 := n.NoiseVec2(x, y); v[0] != v2[0] || v[1] != v2[1] {
+			t.Fatalf("Got %v, expected it to start with %v", v, v2)
+		}
+		return v[:]
+	})
+	for _, a := range c {
+		if math.Abs(a) > 0.05 {
+			t.Errorf("Got correlation %.4f, expected less than 0.05", a)
+		}
+	}
+}
+
+func TestNoiseVec4(t *testing.T) {
+	n := New(rand.New(rand.NewSource(101)))
+
+	c := channelCorrelations(func(x, y float64) []float64 {
+		v := n.NoiseVec4(x, y)
+		if v3 := n.NoiseVec3(x, y); v[0] != v3[0] || v[1] != v3[1] || v[2] != v3[2] {
+			t.Fatalf("Got %v, expected it to start with %v", v, v3)
+		}
+		return v[:]
+	})
+	if len(c) != 6 {
+		t.Fatalf("Got %d channel pairs, expected 6", len(c))
+	}
+	for _, a := range c {
+		if math.Abs(a) > 0.05 {
+			t.Errorf("Got correlation %.4f, expected less than 0.05", a)
+		}
+	}
+}
+
+func TestRGBNoise2(t *testing.T) {
+	n := New(rand.New(rand.NewSource(101)))
+
+	c := channelCorrelations(func(x, y float64) []float64 {
+		r, g, b := n.RGBNoise2(x, y)
+		for _, a := range []float64{r, g, b} {
+			if a < 0 || a > 1 {
+				t.Fatalf("Got %.4f at (%g,%g), expected value in [0,1]", a, x, y)
+			}
+		}
+		return []float64{r, g, b}
+	})
+	for _, a := range c {
+		if math.Abs(a) > 0.05 {
+			t.Errorf("Got correlation %.4f, expected less than 0.05", a)
+		}
+	}
+}
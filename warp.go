@@ -0,0 +1,25 @@
+package simplex
+
+// WarpingField displaces points by noise, for domain warping: sample
+// any other field at the warped coordinates instead of (x, y).  The x
+// and y displacements come from separate noise sources so they are
+// independent of each other.
+type WarpingField struct {
+	WarpX, WarpY *Simplex
+	Strength     float64
+}
+
+// NewWarpingField builds a WarpingField with the x and y displacement
+// sources seeded by seedX and seedY
+func NewWarpingField(seedX, seedY int64, strength float64) *WarpingField {
+	return &WarpingField{
+		WarpX:    NewFromSeed(seedX),
+		WarpY:    NewFromSeed(seedY),
+		Strength: strength,
+	}
+}
+
+// Apply returns (x, y) moved by up to Strength along each axis
+func (wf *WarpingField) Apply(x, y float64) (wx, wy float64) {
+	return x + wf.Strength*wf.WarpX.Noise2(x, y), y + wf.Strength*wf.WarpY.Noise2(x, y)
+}
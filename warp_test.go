@@ -0,0 +1,41 @@
+package simplex
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestWarpingField(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+
+	still := NewWarpingField(1, 2, 0)
+	for i := 0; i < 1000; i++ {
+		x := r.Float64() * 100
+		y := r.Float64() * 100
+		if wx, wy := still.Apply(x, y); wx != x || wy != y {
+			t.Fatalf("got (%g,%g) from (%g,%g) with no strength", wx, wy, x, y)
+		}
+	}
+
+	wf := NewWarpingField(1, 2, 0.5)
+	var dxs, dys []float64
+	const e = 1e-6
+	for i := 0; i < 10000; i++ {
+		x := r.Float64() * 100
+		y := r.Float64() * 100
+		wx, wy := wf.Apply(x, y)
+		if math.Abs(wx-x) > 0.5 || math.Abs(wy-y) > 0.5 {
+			t.Fatalf("got (%g,%g) from (%g,%g), moved more than the strength", wx, wy, x, y)
+		}
+		wx2, wy2 := wf.Apply(x+e, y-e)
+		if math.Abs(wx2-wx) > 10*e || math.Abs(wy2-wy) > 10*e {
+			t.Fatalf("got a jump from (%g,%g) to (%g,%g)", wx, wy, wx2, wy2)
+		}
+		dxs = append(dxs, wx-x)
+		dys = append(dys, wy-y)
+	}
+	if c := correlation(dxs, dys); math.Abs(c) > 0.05 {
+		t.Errorf("got correlation %.4f between x and y displacements, expected about 0", c)
+	}
+}
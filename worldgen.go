@@ -0,0 +1,368 @@
+package simplex
+
+import (
+	"math"
+	"math/rand"
+)
+
+// MarkovTileWorld2 generates a w x h tile map (indexed [y][x]) by
+// running a Markov chain along each row.  The next tile type is drawn
+// from the row of the transition matrix for the previous tile, using a
+// uniform variate derived from Noise2 at (x*step, y*step).  The first
+// tile of each row continues the chain from the end of the row above,
+// so over a large map the tile frequencies approach the stationary
+// distribution of the chain.  Rows of the matrix need not be
+// normalized; an all-zero row keeps the current tile type.  A negative
+// w or h is taken as 0.
+func MarkovTileWorld2(s *Simplex, w, h int, transition [16][16]float64, step float64) [][]uint8 {
+	if w < 0 {
+		w = 0
+	}
+	if h < 0 {
+		h = 0
+	}
+	world := make([][]uint8, h)
+	var cur uint8
+	for y := 0; y < h; y++ {
+		world[y] = make([]uint8, w)
+		for x := 0; x < w; x++ {
+			cur = markovStep(&transition[cur], cur, noiseUniform(s.Noise2(float64(x)*step, float64(y)*step)))
+			world[y][x] = cur
+		}
+	}
+	return world
+}
+
+// noiseUniform turns a noise value into a variate in [0,1).  Noise
+// values are far from uniformly distributed, so we keep only the low
+// order digits, which are.
+func noiseUniform(n float64) float64 {
+	u := (n + 1) * 4096
+	return u - math.Floor(u)
+}
+
+func markovStep(row *[16]float64, cur uint8, u float64) uint8 {
+	var total float64
+	for _, p := range row {
+		total += p
+	}
+	if total <= 0 {
+		return cur
+	}
+	u *= total
+	for i, p := range row {
+		if u < p {
+			return uint8(i)
+		}
+		u -= p
+	}
+	// rounding; fall back to the last state with any weight
+	for i := len(row) - 1; i >= 0; i-- {
+		if row[i] > 0 {
+			return uint8(i)
+		}
+	}
+	return cur
+}
+
+// ArenaLayout describes a boss arena on a w x h grid of cells.  The
+// walls, pillars and hazards grids are indexed [y][x].
+type ArenaLayout struct {
+	Width, Height int
+	// Walls marks the room boundary
+	Walls [][]bool
+	// Pillars lists the (x,y) cells holding a pillar
+	Pillars [][2]int
+	// Hazards marks cells that can be walked over but hurt
+	Hazards [][]bool
+	// PlayerSpawn and BossSpawn are the (x,y) spawn cells
+	PlayerSpawn, BossSpawn [2]int
+}
+
+// Blocked reports whether the cell at (x,y) cannot be walked through
+func (a *ArenaLayout) Blocked(x, y int) bool {
+	if x < 0 || y < 0 || x >= a.Width || y >= a.Height || a.Walls[y][x] {
+		return true
+	}
+	for _, p := range a.Pillars {
+		if p[0] == x && p[1] == y {
+			return true
+		}
+	}
+	return false
+}
+
+// Connected reports whether the boss can be reached from the player
+// spawn, using a breadth-first search over unblocked cells
+func (a *ArenaLayout) Connected() bool {
+	blocked := a.blockedGrid()
+	seen := make([]bool, a.Width*a.Height)
+	queue := [][2]int{a.PlayerSpawn}
+	seen[a.PlayerSpawn[1]*a.Width+a.PlayerSpawn[0]] = true
+	for len(queue) > 0 {
+		c := queue[0]
+		queue = queue[1:]
+		if c == a.BossSpawn {
+			return true
+		}
+		for _, d := range [4][2]int{{1, 0}, {-1, 0}, {0, 1}, {0, -1}} {
+			x, y := c[0]+d[0], c[1]+d[1]
+			if x < 0 || y < 0 || x >= a.Width || y >= a.Height {
+				continue
+			}
+			k := y*a.Width + x
+			if blocked[k] || seen[k] {
+				continue
+			}
+			seen[k] = true
+			queue = append(queue, [2]int{x, y})
+		}
+	}
+	return false
+}
+
+func (a *ArenaLayout) blockedGrid() []bool {
+	blocked := make([]bool, a.Width*a.Height)
+	for y := 0; y < a.Height; y++ {
+		for x := 0; x < a.Width; x++ {
+			blocked[y*a.Width+x] = a.Walls[y][x]
+		}
+	}
+	for _, p := range a.Pillars {
+		blocked[p[1]*a.Width+p[0]] = true
+	}
+	return blocked
+}
+
+// minArenaSize is the smallest width and height of an ArenaLayout2
+const minArenaSize = 6
+
+// ArenaLayout2 lays out a boss arena from noise thresholds.  The room
+// is the set of cells inside a noise-perturbed ellipse, pillars sit at
+// sparse noise peaks and hazards cover the deepest noise troughs.  The
+// seed picks where in the noise field the arena is sampled from.  The
+// player spawns at the left of the room and the boss at the right, on
+// a row with no walls between them.  If the BFS check finds pillars
+// cutting the two off from each other, the pillars on that row are
+// cleared, which always opens a path.  The arena is at
+// least minArenaSize cells each way (smaller sizes are raised to it),
+// which keeps the spawns apart and inside the boundary wall.
+func ArenaLayout2(s *Simplex, w, h int, seed int64) ArenaLayout {
+	if w < minArenaSize {
+		w = minArenaSize
+	}
+	if h < minArenaSize {
+		h = minArenaSize
+	}
+	r := rand.New(rand.NewSource(seed))
+	ox := r.Float64() * 1000
+	oy := r.Float64() * 1000
+
+	a := ArenaLayout{
+		Width:   w,
+		Height:  h,
+		Walls:   make([][]bool, h),
+		Hazards: make([][]bool, h),
+	}
+	cx := float64(w-1) / 2
+	cy := float64(h-1) / 2
+	for y := 0; y < h; y++ {
+		a.Walls[y] = make([]bool, w)
+		a.Hazards[y] = make([]bool, w)
+		for x := 0; x < w; x++ {
+			if x == 0 || y == 0 || x == w-1 || y == h-1 {
+				a.Walls[y][x] = true
+				continue
+			}
+			dx := (float64(x) - cx) / cx
+			dy := (float64(y) - cy) / cy
+			edge := 0.85 + 0.15*s.Noise2(ox+float64(x)*0.15, oy+float64(y)*0.15)
+			a.Walls[y][x] = dx*dx+dy*dy > edge*edge
+		}
+	}
+
+	a.PlayerSpawn = [2]int{int(cx - cx*0.6), int(cy)}
+	a.BossSpawn = [2]int{int(cx + cx*0.6), int(cy)}
+	for x := a.PlayerSpawn[0]; x <= a.BossSpawn[0]; x++ {
+		// the spawn row is always open floor
+		a.Walls[a.BossSpawn[1]][x] = false
+	}
+
+	for y := 1; y < h-1; y++ {
+		for x := 1; x < w-1; x++ {
+			if a.Walls[y][x] {
+				continue
+			}
+			p := [2]int{x, y}
+			if p == a.PlayerSpawn || p == a.BossSpawn {
+				continue
+			}
+			if x%3 == 0 && y%3 == 0 && s.Noise2(ox+float64(x)*0.3+500, oy+float64(y)*0.3) > 0.35 {
+				a.Pillars = append(a.Pillars, p)
+			} else if s.Noise2(ox+float64(x)*0.1-500, oy+float64(y)*0.1) < -0.5 {
+				a.Hazards[y][x] = true
+			}
+		}
+	}
+
+	if !a.Connected() {
+		a.clearSpawnRow()
+	}
+	return a
+}
+
+func (a *ArenaLayout) clearSpawnRow() {
+	keep := a.Pillars[:0]
+	for _, p := range a.Pillars {
+		if p[1] == a.PlayerSpawn[1] && p[0] >= a.PlayerSpawn[0] && p[0] <= a.BossSpawn[0] {
+			continue
+		}
+		keep = append(keep, p)
+	}
+	a.Pillars = keep
+}
+
+// RiverDelta2 traces a river delta over a w x h map starting from
+// (sourceX, sourceY).  The terrain is Noise2 sampled at step spacing,
+// tilted down away from the center of the map so that water always
+// finds its way to the boundary without pooling in local minima.  Each
+// channel follows the steepest descent one unit at a time; at every
+// step it may fork (with a probability driven by the noise) until a
+// total of branches extra channels exist.  The result is one polyline per
+// channel, the first being the main river.
+func RiverDelta2(s *Simplex, sourceX, sourceY float64, w, h int, step float64, branches int) [][][2]float64 {
+	fw := float64(w)
+	fh := float64(h)
+	cx := fw / 2
+	cy := fh / 2
+	inside := func(x, y float64) bool {
+		return x >= 0 && y >= 0 && x < fw && y < fh
+	}
+
+	type channel struct {
+		x, y   float64
+		bias   float64 // turn away from the parent channel
+		points [][2]float64
+	}
+	active := []*channel{{x: sourceX, y: sourceY}}
+	var done [][][2]float64
+	maxSteps := 4 * (w + h)
+	forked := 0
+
+	for len(active) > 0 {
+		c := active[0]
+		active = active[1:]
+		c.points = append(c.points, [2]float64{c.x, c.y})
+		for n := 0; n < maxSteps && inside(c.x, c.y); n++ {
+			const eps = 0.5
+			gx := s.Noise2((c.x+eps)*step, c.y*step) - s.Noise2((c.x-eps)*step, c.y*step)
+			gy := s.Noise2(c.x*step, (c.y+eps)*step) - s.Noise2(c.x*step, (c.y-eps)*step)
+			// the tilt always outweighs the noise, so we keep
+			// making progress toward the edge
+			vx, vy := c.x-cx, c.y-cy
+			if d := math.Hypot(vx, vy); d > 0 {
+				vx /= d
+				vy /= d
+			} else {
+				vx = 1
+			}
+			if g := math.Hypot(gx, gy); g > 0 {
+				vx -= 0.6 * gx / g
+				vy -= 0.6 * gy / g
+			}
+			angle := math.Atan2(vy, vx) + c.bias
+			c.bias *= 0.9
+			c.x += math.Cos(angle)
+			c.y += math.Sin(angle)
+			c.points = append(c.points, [2]float64{c.x, c.y})
+
+			if forked < branches && noiseUniform(s.Noise2(c.x*step+101.3, c.y*step-47.9)) < 0.05 {
+				forked++
+				side := 0.5
+				if forked%2 == 0 {
+					side = -0.5
+				}
+				active = append(active, &channel{x: c.x, y: c.y, bias: side})
+			}
+		}
+		done = append(done, c.points)
+	}
+	return done
+}
+
+// SeabedNoise2 returns the water depth and the thickness of the
+// sediment on the sea floor at (x, y), both in meters.  A broad FBM2
+// field is pushed through a sigmoid to give a continental shelf around
+// 100m deep that falls away to an abyssal plain around 4000m deep,
+// with finer FBM2 detail on top.  The sediment thickness, up to 200m
+// and thicker on the shelf, is driven by FBM2 sampled far away from the
+// depth fields (at an offset that isn't a multiple of the 256 cell
+// period of the lattice hashing), so that it doesn't simply track the
+// depth.
+func SeabedNoise2(s *Simplex, x, y float64) (depth, sedimentThickness float64) {
+	shelf := 1 / (1 + math.Exp(-8*s.FBM2(x*0.01, y*0.01, 4, 2, 0.5)))
+	depth = 100 + 3900*shelf + 50*s.FBM2(x*0.1, y*0.1, 4, 2, 0.5)
+
+	sediment := s.FBM2(x*0.05+sedimentOffsetX, y*0.05+sedimentOffsetY, 3, 2, 0.5)
+	sedimentThickness = 200 * (sediment + 1) / 2 * (1 - 0.5*shelf)
+	return depth, sedimentThickness
+}
+
+// where SeabedNoise2 samples the sediment field
+const (
+	sedimentOffsetX = 431.7
+	sedimentOffsetY = -289.3
+)
+
+// IslandClusters2 generates a w x h archipelago (indexed [y][x]).  The
+// height at each cell is FBM2 sampled at (x, y)*step*islandFrequency,
+// and anything above seaLevel is land.  Each landmass (4-connected
+// group of land cells) is labeled with its own ID, counting up from
+// 1; sea cells have ID 0.
+func IslandClusters2(s *Simplex, w, h int, step float64, seaLevel, islandFrequency float64) (heightmap [][]float32, islandIDs [][]int) {
+	if w < 0 {
+		w = 0
+	}
+	if h < 0 {
+		h = 0
+	}
+	f := step * islandFrequency
+	heightmap = make([][]float32, h)
+	islandIDs = make([][]int, h)
+	for y := 0; y < h; y++ {
+		heightmap[y] = make([]float32, w)
+		islandIDs[y] = make([]int, w)
+		for x := 0; x < w; x++ {
+			heightmap[y][x] = float32(s.FBM2(float64(x)*f, float64(y)*f, 4, 2, 0.5))
+		}
+	}
+
+	next := 1
+	var stack [][2]int
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			if islandIDs[y][x] != 0 || float64(heightmap[y][x]) <= seaLevel {
+				continue
+			}
+			islandIDs[y][x] = next
+			stack = append(stack[:0], [2]int{x, y})
+			for len(stack) > 0 {
+				c := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				for _, d := range [4][2]int{{1, 0}, {-1, 0}, {0, 1}, {0, -1}} {
+					nx, ny := c[0]+d[0], c[1]+d[1]
+					if nx < 0 || ny < 0 || nx >= w || ny >= h {
+						continue
+					}
+					if islandIDs[ny][nx] != 0 || float64(heightmap[ny][nx]) <= seaLevel {
+						continue
+					}
+					islandIDs[ny][nx] = next
+					stack = append(stack, [2]int{nx, ny})
+				}
+			}
+			next++
+		}
+	}
+	return heightmap, islandIDs
+}
@@ -0,0 +1,208 @@
+package simplex
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestMarkovTileWorld2(t *testing.T) {
+	n := New(rand.New(rand.NewSource(101)))
+
+	// a two-state chain whose stationary distribution is (0.75, 0.25)
+	var m [16][16]float64
+	m[0][0], m[0][1] = 0.9, 0.1
+	m[1][0], m[1][1] = 0.3, 0.7
+
+	world := MarkovTileWorld2(n, 300, 200, m, 0.37)
+	if len(world) != 200 || len(world[0]) != 300 {
+		t.Fatalf("got %dx%d world, expected 300x200", len(world[0]), len(world))
+	}
+	var count [2]int
+	for _, row := range world {
+		for _, tile := range row {
+			if tile > 1 {
+				t.Fatalf("got tile type %d, expected 0 or 1", tile)
+			}
+			count[tile]++
+		}
+	}
+	p0 := float64(count[0]) / float64(count[0]+count[1])
+	if math.Abs(p0-0.75) > 0.02 {
+		t.Errorf("got tile 0 frequency %.4f, expected %.4f", p0, 0.75)
+	}
+}
+
+func TestMarkovTileWorld2Empty(t *testing.T) {
+	n := New(rand.New(rand.NewSource(101)))
+
+	var m [16][16]float64
+	for _, size := range [][2]int{{0, 0}, {-5, 3}, {3, -5}, {-1, -1}} {
+		world := MarkovTileWorld2(n, size[0], size[1], m, 0.37)
+		for _, row := range world {
+			if len(row) != 0 {
+				t.Errorf("got a row of %d tiles for %v, expected none", len(row), size)
+			}
+		}
+		if size[1] <= 0 && len(world) != 0 {
+			t.Errorf("got %d rows for %v, expected none", len(world), size)
+		}
+	}
+}
+
+func TestArenaLayout2(t *testing.T) {
+	n := New(rand.New(rand.NewSource(101)))
+
+	for seed := int64(0); seed < 50; seed++ {
+		a := ArenaLayout2(n, 40, 30, seed)
+		if len(a.Walls) != 30 || len(a.Walls[0]) != 40 {
+			t.Fatalf("got %dx%d walls, expected 40x30", len(a.Walls[0]), len(a.Walls))
+		}
+		if !a.Connected() {
+			t.Errorf("seed %d: boss is not reachable from the player spawn", seed)
+		}
+		if a.Blocked(a.PlayerSpawn[0], a.PlayerSpawn[1]) || a.Blocked(a.BossSpawn[0], a.BossSpawn[1]) {
+			t.Errorf("seed %d: spawn point is blocked", seed)
+		}
+		for x := 0; x < 40; x++ {
+			if !a.Walls[0][x] || !a.Walls[29][x] {
+				t.Fatalf("seed %d: missing boundary wall at x=%d", seed, x)
+			}
+		}
+	}
+}
+
+func TestArenaLayout2Small(t *testing.T) {
+	n := New(rand.New(rand.NewSource(101)))
+
+	for _, size := range [][2]int{{0, 0}, {1, 1}, {2, 2}, {-3, 4}, {5, 5}, {6, 6}, {40, 0}} {
+		a := ArenaLayout2(n, size[0], size[1], 7)
+		if a.Width < minArenaSize || a.Height < minArenaSize || a.Width < size[0] || a.Height < size[1] {
+			t.Fatalf("got %dx%d arena for %v, expected at least %dx%d", a.Width, a.Height, size, minArenaSize, minArenaSize)
+		}
+		if len(a.Walls) != a.Height || len(a.Walls[0]) != a.Width {
+			t.Fatalf("got %dx%d walls, expected %dx%d", len(a.Walls[0]), len(a.Walls), a.Width, a.Height)
+		}
+		if a.PlayerSpawn == a.BossSpawn {
+			t.Errorf("got both spawns at %v for %v", a.PlayerSpawn, size)
+		}
+		for _, p := range [][2]int{a.PlayerSpawn, a.BossSpawn} {
+			if p[0] <= 0 || p[1] <= 0 || p[0] >= a.Width-1 || p[1] >= a.Height-1 || a.Blocked(p[0], p[1]) {
+				t.Errorf("got spawn %v in a %dx%d arena, expected an open interior cell", p, a.Width, a.Height)
+			}
+		}
+		if !a.Connected() {
+			t.Errorf("got an unconnected arena for %v", size)
+		}
+	}
+}
+
+func TestRiverDelta2(t *testing.T) {
+	n := New(rand.New(rand.NewSource(101)))
+
+	delta := RiverDelta2(n, 50, 50, 100, 100, 0.05, 6)
+	if len(delta) < 2 || len(delta) > 7 {
+		t.Fatalf("got %d channels, expected between 2 and 7", len(delta))
+	}
+	if p := delta[0][0]; p != [2]float64{50, 50} {
+		t.Errorf("main channel starts at %v, expected the source", p)
+	}
+	for i, c := range delta {
+		end := c[len(c)-1]
+		if end[0] >= 0 && end[1] >= 0 && end[0] < 100 && end[1] < 100 {
+			t.Errorf("channel %d ends at %v, inside the map", i, end)
+		}
+		for j := 1; j < len(c); j++ {
+			dx := c[j][0] - c[j-1][0]
+			dy := c[j][1] - c[j-1][1]
+			if math.Abs(dx*dx+dy*dy-1) > 1e-9 {
+				t.Fatalf("channel %d has a step of length %g", i, math.Sqrt(dx*dx+dy*dy))
+			}
+		}
+	}
+}
+
+func TestSeabedNoise2(t *testing.T) {
+	r := rand.New(rand.NewSource(101))
+	n := New(r)
+
+	var depths, sediments []float64
+	shallow, deep := 0, 0
+	for i := 0; i < 10000; i++ {
+		x := r.Float64() * 1000
+		y := r.Float64() * 1000
+		d, sed := SeabedNoise2(n, x, y)
+		if d < 50 || d > 4050 {
+			t.Fatalf("got depth %.1f, expected value in [50,4050]", d)
+		}
+		if sed < 0 || sed > 200 {
+			t.Fatalf("got sediment %.1f, expected value in [0,200]", sed)
+		}
+		if d < 500 {
+			shallow++
+		} else if d > 3000 {
+			deep++
+		}
+		depths = append(depths, d)
+		sediments = append(sediments, sed)
+	}
+	if shallow == 0 || deep == 0 {
+		t.Errorf("got %d shelf and %d abyssal samples, expected both", shallow, deep)
+	}
+	if c := correlation(depths, sediments); c < -0.9 {
+		t.Errorf("got correlation %.4f, sediment is just tracking depth", c)
+	}
+}
+
+func TestIslandClusters2(t *testing.T) {
+	n := New(rand.New(rand.NewSource(101)))
+
+	const seaLevel = 0.2
+	hm, ids := IslandClusters2(n, 120, 80, 0.1, seaLevel, 0.5)
+	if len(hm) != 80 || len(hm[0]) != 120 || len(ids) != 80 || len(ids[0]) != 120 {
+		t.Fatalf("got wrong dimensions")
+	}
+	islands := map[int]bool{}
+	for y := 0; y < 80; y++ {
+		for x := 0; x < 120; x++ {
+			land := float64(hm[y][x]) > seaLevel
+			if land != (ids[y][x] > 0) {
+				t.Fatalf("cell (%d,%d) has height %.4f but island ID %d", x, y, hm[y][x], ids[y][x])
+			}
+			if !land {
+				continue
+			}
+			islands[ids[y][x]] = true
+			if x > 0 && ids[y][x-1] > 0 && ids[y][x-1] != ids[y][x] {
+				t.Fatalf("neighboring land cells have IDs %d and %d", ids[y][x-1], ids[y][x])
+			}
+			if y > 0 && ids[y-1][x] > 0 && ids[y-1][x] != ids[y][x] {
+				t.Fatalf("neighboring land cells have IDs %d and %d", ids[y-1][x], ids[y][x])
+			}
+		}
+	}
+	if len(islands) < 2 {
+		t.Errorf("got %d islands, expected an archipelago", len(islands))
+	}
+	for id := 1; id <= len(islands); id++ {
+		if !islands[id] {
+			t.Errorf("island IDs are not consecutive; %d is missing", id)
+		}
+	}
+}
+
+func TestIslandClusters2Empty(t *testing.T) {
+	n := New(rand.New(rand.NewSource(101)))
+
+	for _, size := range [][2]int{{0, 0}, {-5, 3}, {3, -5}, {-1, -1}} {
+		hm, ids := IslandClusters2(n, size[0], size[1], 0.1, 0.2, 0.5)
+		for y := range hm {
+			if len(hm[y]) != 0 || len(ids[y]) != 0 {
+				t.Errorf("got a row of %d cells for %v, expected none", len(hm[y]), size)
+			}
+		}
+		if size[1] <= 0 && (len(hm) != 0 || len(ids) != 0) {
+			t.Errorf("got %d rows for %v, expected none", len(hm), size)
+		}
+	}
+}